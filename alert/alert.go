@@ -0,0 +1,166 @@
+// Package alert evaluates rules over Magic Seaweed forecasts, such as "primary
+// period >= 12 and wind speed < 10 and solidRating >= 3 at spot 391 this
+// weekend", and reports each window of matching forecasts as an Event exactly
+// once across repeated polls.
+package alert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// When selects the forecasts against which a Rule is evaluated.
+type When string
+
+const (
+	// Anytime evaluates a Rule against a spot's full, multi-day forecast.
+	Anytime When = ""
+	// Today evaluates a Rule against today's forecast.
+	Today When = "today"
+	// Tomorrow evaluates a Rule against tomorrow's forecast.
+	Tomorrow When = "tomorrow"
+	// Weekend evaluates a Rule against the weekend's forecast.
+	Weekend When = "weekend"
+)
+
+// Rule is a named condition evaluated against a spot's forecasts.
+type Rule struct {
+	// Name identifies the rule; it distinguishes the events of rules sharing
+	// a spot.
+	Name string
+	// Spot is the Magic Seaweed spot ID.
+	Spot string
+	// When selects the forecasts against which the rule is evaluated.
+	When When
+	// Expr is the condition each forecast timestep is tested against.
+	Expr *Expr
+}
+
+// Event is a run of consecutive forecast timesteps matching a Rule.
+type Event struct {
+	// Rule is the name of the matched rule.
+	Rule string
+	// Spot is the spot ID of the matched rule.
+	Spot string
+	// Start is the timestamp of the first matching forecast.
+	Start time.Time
+	// End is the timestamp of the last matching forecast.
+	End time.Time
+	// Forecasts are the matching forecasts.
+	Forecasts []seaweed.Forecast
+}
+
+// key identifies the event's spot and rule. Events sharing a key are
+// deduplicated by their time ranges; see Store.
+func (e Event) key() string {
+	return e.Spot + "/" + e.Rule
+}
+
+// overlaps returns true if the event's time range overlaps that of w,
+// inclusively.
+func (e Event) overlaps(w window) bool {
+	return !e.Start.After(w.End) && !w.Start.After(e.End)
+}
+
+// Events groups the consecutive forecasts matching the rule into events. The
+// forecasts are expected to be ordered by timestamp, as the Magic Seaweed API
+// returns them.
+func Events(rule Rule, forecasts []seaweed.Forecast) []Event {
+	var events []Event
+	var current *Event
+
+	for _, f := range forecasts {
+		if !rule.Expr.Match(f) {
+			current = nil
+			continue
+		}
+
+		ts := time.Unix(int64(f.Timestamp), 0).UTC()
+		if current == nil {
+			events = append(events, Event{Rule: rule.Name, Spot: rule.Spot, Start: ts})
+			current = &events[len(events)-1]
+		}
+
+		current.End = ts
+		current.Forecasts = append(current.Forecasts, f)
+	}
+
+	return events
+}
+
+// Forecaster fetches forecasts for a spot. *seaweed.Client implements
+// Forecaster.
+type Forecaster interface {
+	Forecast(spot string) ([]seaweed.Forecast, error)
+	Today(spot string) ([]seaweed.Forecast, error)
+	Tomorrow(spot string) ([]seaweed.Forecast, error)
+	Weekend(spot string) ([]seaweed.Forecast, error)
+}
+
+// Engine polls forecasts for a set of rules and reports new events.
+type Engine struct {
+	// Forecaster fetches forecasts.
+	Forecaster Forecaster
+	// Store records which events have already fired.
+	Store Store
+}
+
+// Poll fetches each rule's forecasts and returns the events that have not
+// previously fired, recording them as fired in the Engine's Store. An event
+// overlapping the time range of an event of the same spot and rule that has
+// already fired, such as the same event under way, does not fire again.
+//
+// A rule whose forecasts cannot be fetched is skipped; Poll returns the events
+// of the remaining rules alongside the first such error.
+func (e *Engine) Poll(rules []Rule) ([]Event, error) {
+	var fired []Event
+	var firstErr error
+
+	for _, rule := range rules {
+		forecasts, err := e.fetch(rule)
+		if err != nil && !seaweed.IsStale(err) {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("fetching forecast for rule %q: %w", rule.Name, err)
+			}
+
+			continue
+		}
+
+		for _, event := range Events(rule, forecasts) {
+			ok, err := e.Store.Fired(event)
+			if err != nil {
+				return fired, err
+			}
+
+			// Events that have fired are recorded again, such that the
+			// recorded time range follows an event under way as it grows, or
+			// as its earlier timesteps pass out of the forecast.
+			if err := e.Store.MarkFired(event); err != nil {
+				return fired, err
+			}
+
+			if !ok {
+				fired = append(fired, event)
+			}
+		}
+	}
+
+	return fired, firstErr
+}
+
+func (e *Engine) fetch(rule Rule) ([]seaweed.Forecast, error) {
+	switch rule.When {
+	case Anytime:
+		return e.Forecaster.Forecast(rule.Spot)
+	case Today:
+		return e.Forecaster.Today(rule.Spot)
+	case Tomorrow:
+		return e.Forecaster.Tomorrow(rule.Spot)
+	case Weekend:
+		return e.Forecaster.Weekend(rule.Spot)
+	default:
+		return nil, fmt.Errorf("unknown When %q", rule.When)
+	}
+}
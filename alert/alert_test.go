@@ -0,0 +1,186 @@
+package alert
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+func timesteps(ratings ...int) []seaweed.Forecast {
+	forecasts := make([]seaweed.Forecast, len(ratings))
+	for i, r := range ratings {
+		forecasts[i] = seaweed.Forecast{
+			Timestamp:   seaweed.FlexInt64(1677973200 + i*3*60*60),
+			SolidRating: seaweed.FlexInt(r),
+		}
+	}
+
+	return forecasts
+}
+
+func TestEvents(t *testing.T) {
+	rule := Rule{Name: "good", Spot: "391", Expr: MustCompile("solidRating >= 3")}
+	events := Events(rule, timesteps(1, 3, 4, 2, 3, 1, 5))
+
+	expected := []struct {
+		start, end int64
+		count      int
+	}{
+		{1677973200 + 3*60*60, 1677973200 + 6*60*60, 2},
+		{1677973200 + 12*60*60, 1677973200 + 12*60*60, 1},
+		{1677973200 + 18*60*60, 1677973200 + 18*60*60, 1},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected '%d' events; got '%d'", len(expected), len(events))
+	}
+
+	for i, e := range expected {
+		if events[i].Start.Unix() != e.start || events[i].End.Unix() != e.end || len(events[i].Forecasts) != e.count {
+			t.Errorf("expected event %d to span %d-%d with %d forecasts; got %d-%d with %d", i, e.start, e.end, e.count, events[i].Start.Unix(), events[i].End.Unix(), len(events[i].Forecasts))
+		}
+
+		if events[i].Rule != "good" || events[i].Spot != "391" {
+			t.Errorf("expected event %d to be of rule 'good' at spot '391'; got '%s' at '%s'", i, events[i].Rule, events[i].Spot)
+		}
+	}
+}
+
+type fakeForecaster struct {
+	forecasts []seaweed.Forecast
+	err       error
+	calls     []string
+}
+
+func (f *fakeForecaster) respond(call string) ([]seaweed.Forecast, error) {
+	f.calls = append(f.calls, call)
+	return f.forecasts, f.err
+}
+
+func (f *fakeForecaster) Forecast(spot string) ([]seaweed.Forecast, error) {
+	return f.respond("forecast " + spot)
+}
+
+func (f *fakeForecaster) Today(spot string) ([]seaweed.Forecast, error) {
+	return f.respond("today " + spot)
+}
+
+func (f *fakeForecaster) Tomorrow(spot string) ([]seaweed.Forecast, error) {
+	return f.respond("tomorrow " + spot)
+}
+
+func (f *fakeForecaster) Weekend(spot string) ([]seaweed.Forecast, error) {
+	return f.respond("weekend " + spot)
+}
+
+func TestOpenFileStore_null(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fired.json")
+	if err := os.WriteFile(path, []byte("null"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("expected no error; got '%v'", err)
+	}
+
+	e := Event{Rule: "good", Spot: "391", Start: time.Unix(1677973200, 0), End: time.Unix(1677984000, 0)}
+	if err := store.MarkFired(e); err != nil {
+		t.Fatalf("expected no error; got '%v'", err)
+	}
+
+	if fired, _ := store.Fired(e); !fired {
+		t.Error("expected the event to be marked fired")
+	}
+}
+
+func TestEngine_Poll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fired.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forecaster := &fakeForecaster{forecasts: timesteps(1, 3, 4, 1)}
+	engine := &Engine{Forecaster: forecaster, Store: store}
+	rules := []Rule{
+		{Name: "good", Spot: "391", When: Weekend, Expr: MustCompile("solidRating >= 3")},
+		{Name: "epic", Spot: "391", When: Today, Expr: MustCompile("solidRating >= 5")},
+	}
+
+	events, err := engine.Poll(rules)
+	if err != nil {
+		t.Fatalf("expected Poll not to error; got '%v'", err)
+	}
+
+	if len(events) != 1 || len(events[0].Forecasts) != 2 {
+		t.Fatalf("expected '1' event of '2' forecasts; got '%v'", events)
+	}
+
+	if forecaster.calls[0] != "weekend 391" || forecaster.calls[1] != "today 391" {
+		t.Errorf("expected rules' When to select forecasts; got calls '%v'", forecaster.calls)
+	}
+
+	// The event grows, but shares its start.
+	forecaster.forecasts = timesteps(1, 3, 4, 4)
+	if events, _ := engine.Poll(rules); len(events) != 0 {
+		t.Errorf("expected no events to fire again; got '%v'", events)
+	}
+
+	// The event under way loses its earlier timesteps, such as overnight, and
+	// grows later.
+	forecaster.forecasts = timesteps(1, 3, 4, 4, 3)[3:]
+	if events, _ := engine.Poll(rules); len(events) != 0 {
+		t.Errorf("expected the event under way not to fire again; got '%v'", events)
+	}
+
+	// A new issue extends the event earlier.
+	forecaster.forecasts = timesteps(3, 3, 4, 4, 3)
+	if events, _ := engine.Poll(rules); len(events) != 0 {
+		t.Errorf("expected the extended event not to fire again; got '%v'", events)
+	}
+
+	// A reopened store remembers fired events.
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Store = reopened
+	forecaster.forecasts = timesteps(1, 3, 1, 1, 1, 1, 4)
+	events, err = engine.Poll(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Start.Unix() != 1677973200+18*60*60 {
+		t.Errorf("expected only the new event to fire; got '%v'", events)
+	}
+
+	if err := reopened.Prune(time.Unix(1677973200+18*60*60+1, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if fired, _ := reopened.Fired(events[0]); fired {
+		t.Error("expected Prune to forget events ending before its cutoff")
+	}
+
+	forecaster.forecasts = timesteps(1, 3)
+	if fired, _ := reopened.Fired(Events(rules[0], forecaster.forecasts)[0]); fired {
+		t.Error("expected Prune to forget every event ending before its cutoff")
+	}
+}
+
+func TestEngine_Poll_error(t *testing.T) {
+	forecaster := &fakeForecaster{err: errors.New("boom")}
+	engine := &Engine{Forecaster: forecaster, Store: NewMemoryStore()}
+
+	_, err := engine.Poll([]Rule{{Name: "good", Spot: "391", Expr: MustCompile("solidRating >= 3")}})
+	if err == nil || err.Error() != `fetching forecast for rule "good": boom` {
+		t.Errorf("expected a fetch error; got '%v'", err)
+	}
+}
@@ -0,0 +1,513 @@
+package alert
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mdb/seaweed"
+)
+
+// Expr is a compiled boolean expression over seaweed.Forecast fields, such as:
+//
+//	swell.components.primary.period >= 12 and wind.speed < 10 and solidRating >= 3
+//
+// Fields are referenced by dotted paths of their JSON names, matched case
+// insensitively. Expressions support the comparison operators ==, !=, <, <=,
+// >, and >=; the boolean operators and, or, and not; parentheses; and number
+// and double-quoted string literals. A comparison involving an absent field,
+// such as a forecast's missing tertiary swell component, is false.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses src into an *Expr, verifying that each of its field paths
+// exists on seaweed.Forecast.
+func Compile(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// MustCompile is like Compile but panics if src cannot be compiled.
+func MustCompile(src string) *Expr {
+	e, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// Match returns true if the forecast satisfies the expression.
+func (e *Expr) Match(f seaweed.Forecast) bool {
+	return e.root.eval(reflect.ValueOf(f)).truthy()
+}
+
+// String returns the expression's source.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// value is the result of evaluating a node.
+type value struct {
+	kind valueKind
+	num  float64
+	str  string
+	b    bool
+}
+
+type valueKind int
+
+const (
+	kindMissing valueKind = iota
+	kindNumber
+	kindString
+	kindBool
+)
+
+func (v value) truthy() bool {
+	return v.kind == kindBool && v.b
+}
+
+type node interface {
+	eval(f reflect.Value) value
+}
+
+type literal struct {
+	v value
+}
+
+func (l literal) eval(reflect.Value) value {
+	return l.v
+}
+
+// field is a resolved field path.
+type field struct {
+	path  string
+	index [][]int
+}
+
+func (fd field) eval(f reflect.Value) value {
+	v := f
+	for _, idx := range fd.index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return value{}
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByIndex(idx)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return value{}
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value{kind: kindNumber, num: float64(v.Int())}
+	case reflect.Float32, reflect.Float64:
+		return value{kind: kindNumber, num: v.Float()}
+	case reflect.String:
+		return value{kind: kindString, str: v.String()}
+	default:
+		return value{}
+	}
+}
+
+// resolve resolves a dotted path of JSON field names against seaweed.Forecast.
+func resolve(path string) (field, error) {
+	t := reflect.TypeOf(seaweed.Forecast{})
+	fd := field{path: path}
+
+	for _, name := range strings.Split(path, ".") {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return fd, fmt.Errorf("unknown field %q", path)
+		}
+
+		sf, ok := fieldByJSONName(t, name)
+		if !ok {
+			return fd, fmt.Errorf("unknown field %q", path)
+		}
+
+		fd.index = append(fd.index, sf.Index)
+		t = sf.Type
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return fd, fmt.Errorf("field %q is not a number or string", path)
+	}
+
+	return fd, nil
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+
+		if strings.EqualFold(jsonName, name) {
+			return sf, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (c comparison) eval(f reflect.Value) value {
+	l, r := c.left.eval(f), c.right.eval(f)
+	if l.kind == kindMissing || r.kind == kindMissing || l.kind != r.kind {
+		return value{kind: kindBool}
+	}
+
+	var cmp int
+	switch l.kind {
+	case kindNumber:
+		cmp = compareFloats(l.num, r.num)
+	case kindString:
+		cmp = strings.Compare(l.str, r.str)
+	default:
+		return value{kind: kindBool}
+	}
+
+	var b bool
+	switch c.op {
+	case "==":
+		b = cmp == 0
+	case "!=":
+		b = cmp != 0
+	case "<":
+		b = cmp < 0
+	case "<=":
+		b = cmp <= 0
+	case ">":
+		b = cmp > 0
+	case ">=":
+		b = cmp >= 0
+	}
+
+	return value{kind: kindBool, b: b}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type logical struct {
+	and         bool
+	left, right node
+}
+
+func (l logical) eval(f reflect.Value) value {
+	left := l.left.eval(f).truthy()
+	if l.and && !left {
+		return value{kind: kindBool}
+	}
+
+	if !l.and && left {
+		return value{kind: kindBool, b: true}
+	}
+
+	return value{kind: kindBool, b: l.right.eval(f).truthy()}
+}
+
+type not struct {
+	operand node
+}
+
+func (n not) eval(f reflect.Value) value {
+	return value{kind: kindBool, b: !n.operand.eval(f).truthy()}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", start})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", start})
+			i++
+		case strings.ContainsRune("<>=!&|", r):
+			op := string(r)
+			if i+1 < len(rs) && strings.ContainsRune("=&|", rs[i+1]) {
+				op += string(rs[i+1])
+			}
+			i += len([]rune(op))
+
+			switch op {
+			case "&&":
+				toks = append(toks, token{tokAnd, op, start})
+			case "||":
+				toks = append(toks, token{tokOr, op, start})
+			case "!":
+				toks = append(toks, token{tokNot, op, start})
+			case "=":
+				toks = append(toks, token{tokOp, "==", start})
+			case "==", "!=", "<", "<=", ">", ">=":
+				toks = append(toks, token{tokOp, op, start})
+			default:
+				return nil, fmt.Errorf("unexpected %q at offset %d", op, start)
+			}
+		case r == '"':
+			i++
+			for i < len(rs) && rs[i] != '"' {
+				if rs[i] == '\\' {
+					i++
+				}
+				i++
+			}
+
+			if i >= len(rs) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			i++
+
+			s, err := strconv.Unquote(string(rs[start:i]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", start, err)
+			}
+			toks = append(toks, token{tokString, s, start})
+		case unicode.IsDigit(r) || r == '-' || r == '.':
+			i++
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, string(rs[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_' || rs[i] == '.') {
+				i++
+			}
+
+			word := string(rs[start:i])
+			switch strings.ToLower(word) {
+			case "and":
+				toks = append(toks, token{tokAnd, word, start})
+			case "or":
+				toks = append(toks, token{tokOr, word, start})
+			case "not":
+				toks = append(toks, token{tokNot, word, start})
+			default:
+				toks = append(toks, token{tokIdent, word, start})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", r, start)
+		}
+	}
+
+	return append(toks, token{kind: tokEOF, pos: len(rs)}), nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.toks[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{and: false, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{and: true, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return not{operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	if p.peek().kind == tokLParen {
+		p.next()
+
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at offset %d; got %s", tok.pos, tok)
+		}
+
+		return n, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.next()
+	if tok.kind != tokOp {
+		return nil, fmt.Errorf("expected comparison operator at offset %d; got %s", tok.pos, tok)
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at offset %d", tok, tok.pos)
+		}
+
+		return literal{value{kind: kindNumber, num: n}}, nil
+	case tokString:
+		return literal{value{kind: kindString, str: tok.text}}, nil
+	case tokIdent:
+		fd, err := resolve(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w at offset %d", err, tok.pos)
+		}
+
+		return fd, nil
+	default:
+		return nil, fmt.Errorf("expected field, number, or string at offset %d; got %s", tok.pos, tok)
+	}
+}
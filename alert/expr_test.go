@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+func testForecast() seaweed.Forecast {
+	return seaweed.Forecast{
+		Timestamp:   1442355356,
+		SolidRating: 3,
+		FadedRating: 1,
+		Swell: seaweed.Swell{
+			Unit: "ft",
+			Components: seaweed.Components{
+				Primary:   seaweed.Component{Height: 4.5, Period: 12, Direction: 90, CompassDirection: "W"},
+				Secondary: &seaweed.Component{Height: 1, Period: 6},
+			},
+		},
+		Wind:      seaweed.Wind{Speed: 8, CompassDirection: "N"},
+		Condition: seaweed.Condition{Weather: "22"},
+	}
+}
+
+func TestExpr_Match(t *testing.T) {
+	tests := []struct {
+		expr   string
+		expect bool
+	}{
+		{"swell.components.primary.period >= 12 and wind.speed < 10 and solidRating >= 3", true},
+		{"swell.components.primary.period > 12", false},
+		{"SWELL.COMPONENTS.PRIMARY.PERIOD == 12", true},
+		{"swell.components.primary.period = 12 && wind.speed != 8", false},
+		{"solidRating >= 4 or fadedRating >= 1", true},
+		{"solidRating >= 4 || fadedRating >= 2", false},
+		{"not solidRating >= 4", true},
+		{"!(solidRating >= 3)", false},
+		{"(solidRating >= 4 or wind.speed < 10) and swell.unit == \"ft\"", true},
+		{"swell.components.primary.compassDirection == \"W\"", true},
+		{"condition.weather == \"22\"", true},
+		{"swell.components.primary.height > swell.components.secondary.height", true},
+		{"swell.components.secondary.period >= 6", true},
+		{"swell.components.tertiary.period >= 0", false},
+		{"not swell.components.tertiary.period >= 0", true},
+		{"wind.speed > -1.5", true},
+		{"swell.unit > 3", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			e, err := Compile(test.expr)
+			if err != nil {
+				t.Fatalf("expected '%s' to compile; got '%v'", test.expr, err)
+			}
+
+			if got := e.Match(testForecast()); got != test.expect {
+				t.Errorf("expected '%s' to evaluate to '%t'; got '%t'", test.expr, test.expect, got)
+			}
+		})
+	}
+}
+
+func TestCompile_errors(t *testing.T) {
+	tests := []struct {
+		expr        string
+		expectError string
+	}{
+		{"", `expected field, number, or string at offset 0; got end of expression`},
+		{"swell.period > 3", `unknown field "swell.period" at offset 0`},
+		{"swell.components > 3", `field "swell.components" is not a number or string at offset 0`},
+		{"solidRating", `expected comparison operator at offset 11; got end of expression`},
+		{"solidRating >= 3 and", `expected field, number, or string at offset 20; got end of expression`},
+		{"(solidRating >= 3", `expected ")" at offset 17; got end of expression`},
+		{"solidRating >= 3)", `unexpected ")" at offset 16`},
+		{"solidRating => 3", `expected field, number, or string at offset 13; got ">"`},
+		{`swell.unit == "ft`, `unterminated string at offset 14`},
+		{"solidRating # 3", `unexpected '#' at offset 12`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			_, err := Compile(test.expr)
+			if err == nil {
+				t.Fatalf("expected '%s' not to compile", test.expr)
+			}
+
+			if err.Error() != test.expectError {
+				t.Errorf("expected error '%s'; got '%s'", test.expectError, err.Error())
+			}
+		})
+	}
+}
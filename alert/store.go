@@ -0,0 +1,164 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mdb/seaweed/internal/atomicfile"
+)
+
+// Store records which events have fired.
+type Store interface {
+	// Fired returns true if an event of the same spot and rule as e, whose
+	// time range overlaps e's, has fired.
+	Fired(e Event) (bool, error)
+	// MarkFired records that e has fired, merging its time range with those
+	// of the overlapping events of the same spot and rule already recorded.
+	MarkFired(e Event) error
+}
+
+// window is the time range of one or more fired events.
+type window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	mu sync.Mutex
+	// fired holds the time ranges of fired events, by spot and rule.
+	fired map[string][]window
+}
+
+// NewMemoryStore returns an empty *MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{fired: map[string][]window{}}
+}
+
+// Fired implements Store.
+func (s *MemoryStore) Fired(e Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range s.fired[e.key()] {
+		if e.overlaps(w) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// MarkFired implements Store.
+func (s *MemoryStore) MarkFired(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := window{Start: e.Start, End: e.End}
+
+	var windows []window
+	for _, w := range s.fired[e.key()] {
+		if !e.overlaps(w) {
+			windows = append(windows, w)
+			continue
+		}
+
+		if w.Start.Before(merged.Start) {
+			merged.Start = w.Start
+		}
+
+		if w.End.After(merged.End) {
+			merged.End = w.End
+		}
+	}
+
+	s.fired[e.key()] = append(windows, merged)
+
+	return nil
+}
+
+// FileStore is a Store persisting fired events to a JSON file, such that
+// events are not re-reported across process restarts.
+type FileStore struct {
+	path string
+	mem  *MemoryStore
+}
+
+// OpenFileStore returns a *FileStore backed by the JSON file at path, loading
+// previously fired events if the file exists.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, mem: NewMemoryStore()}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &s.mem.fired); err != nil {
+		return nil, err
+	}
+
+	// A file of null unmarshals to a nil map.
+	if s.mem.fired == nil {
+		s.mem.fired = map[string][]window{}
+	}
+
+	return s, nil
+}
+
+// Fired implements Store.
+func (s *FileStore) Fired(e Event) (bool, error) {
+	return s.mem.Fired(e)
+}
+
+// MarkFired implements Store, persisting the store's file.
+func (s *FileStore) MarkFired(e Event) error {
+	if err := s.mem.MarkFired(e); err != nil {
+		return err
+	}
+
+	return s.save()
+}
+
+// Prune forgets events that ended before t, which can no longer fire again,
+// so that the store's file does not grow without bound.
+func (s *FileStore) Prune(t time.Time) error {
+	s.mem.mu.Lock()
+	for key, windows := range s.mem.fired {
+		var kept []window
+		for _, w := range windows {
+			if !w.End.Before(t) {
+				kept = append(kept, w)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(s.mem.fired, key)
+		} else {
+			s.mem.fired[key] = kept
+		}
+	}
+	s.mem.mu.Unlock()
+
+	return s.save()
+}
+
+// save atomically writes the store's file.
+func (s *FileStore) save() error {
+	s.mem.mu.Lock()
+	content, err := json.MarshalIndent(s.mem.fired, "", "  ")
+	s.mem.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(s.path, content)
+}
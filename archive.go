@@ -0,0 +1,19 @@
+package seaweed
+
+// Archiver records each issue of a spot's forecasts fetched by a Client, such
+// that forecasts may be evaluated after the fact. See the archive package for
+// a file-backed Archiver.
+type Archiver interface {
+	Archive(spot string, forecasts []Forecast) error
+}
+
+// WithArchive is a ClientOption configuring a *Client to pass each spot's
+// freshly fetched forecasts to an Archiver. Forecasts of the same issue as the
+// spot's previously fetched forecasts, such as those revalidated via a 304
+// response, and forecasts served stale are not re-archived. A failure to
+// archive is logged rather than failing the fetch.
+func WithArchive(a Archiver) ClientOption {
+	return func(c *Client) {
+		c.archiver = a
+	}
+}
@@ -0,0 +1,430 @@
+// Package archive persists every issue of each spot's Magic Seaweed forecasts
+// to an append-only, gzip-compressed JSON-lines file layout, and queries the
+// archived issues by the times they cover, such that forecasts may be evaluated
+// after the fact.
+//
+// An archive directory holds one segment file per spot per UTC day of issue,
+// such as 391/2026-10-18.jsonl.gz, or 391/2026-10-18.1.jsonl.gz once
+// compacted (see Archive.Compact). Each archived issue is appended to its
+// segment as a gzip member holding a single JSON line; concatenated members are
+// themselves a valid gzip stream. An index.jsonl file records where each issue
+// lives and the span of time its forecasts cover, so that queries need not
+// decompress unrelated segments.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+const (
+	// indexFile is the name of an archive directory's index.
+	indexFile = "index.jsonl"
+	// segmentExt is the file extension of an archive directory's segments.
+	segmentExt = ".jsonl.gz"
+)
+
+// Issue is a single issue of a spot's forecasts.
+type Issue struct {
+	// Spot is the Magic Seaweed spot ID.
+	Spot string `json:"spot"`
+	// IssuedAt is the most recent IssueTimestamp among the forecasts.
+	IssuedAt time.Time `json:"issued"`
+	// FetchedAt is the time at which the forecasts were archived.
+	FetchedAt time.Time `json:"fetched"`
+	// Forecasts are the issue's forecasts.
+	Forecasts []seaweed.Forecast `json:"forecasts"`
+}
+
+// entry is an index record locating an archived issue.
+type entry struct {
+	Spot   string `json:"spot"`
+	Issued int64  `json:"issued"`
+	// Start and End are the earliest and latest forecast Timestamps in the
+	// issue.
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	// Segment is the path of the issue's segment, relative to the archive
+	// directory.
+	Segment string `json:"segment"`
+	// Offset and Length locate the gzip member holding the issue within its
+	// segment.
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+}
+
+// Archive is a directory of archived forecast issues. It implements
+// seaweed.Archiver, such that it may be hooked into a *seaweed.Client via
+// seaweed.WithArchive. An Archive is safe for concurrent use.
+type Archive struct {
+	// dir is the archive directory.
+	dir string
+	// retention is how long after their issue Compact retains issues; issues
+	// are retained indefinitely if it is zero.
+	retention time.Duration
+	// clock reports the current time.
+	clock seaweed.Clock
+	// mu guards the archive's files and index.
+	mu sync.Mutex
+	// index holds the archive's index records, ordered by issue time.
+	index []entry
+}
+
+// Option configures one or more Archive fields.
+type Option = func(a *Archive)
+
+// WithRetention is an Option configuring how long after their issue an
+// *Archive's Compact retains issues. By default, issues are retained
+// indefinitely.
+func WithRetention(retention time.Duration) Option {
+	return func(a *Archive) {
+		a.retention = retention
+	}
+}
+
+// WithClock is an Option to configure an *Archive's clock.
+func WithClock(clock seaweed.Clock) Option {
+	return func(a *Archive) {
+		a.clock = clock
+	}
+}
+
+// Open returns an *Archive backed by dir, creating dir if it does not exist and
+// loading its index if it does.
+func Open(dir string, opts ...Option) (*Archive, error) {
+	a := &Archive{
+		dir:   dir,
+		clock: seaweed.RealClock{},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	index, truncated, err := readIndex(filepath.Join(dir, indexFile))
+	if err != nil {
+		return nil, err
+	}
+	a.index = index
+
+	// Rewrite a truncated index, such that subsequent appends begin on a new
+	// line.
+	if truncated {
+		if err := a.writeIndex(); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// readIndex reads the index file at path. A truncated final line, such as is
+// left by a crash mid-append, is ignored, in which case readIndex reports
+// that the index is truncated.
+func readIndex(path string) ([]entry, bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+
+	var index []entry
+	for i, line := range lines {
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if i == len(lines)-1 {
+				sortIndex(index)
+
+				return index, true, nil
+			}
+
+			return nil, false, fmt.Errorf("reading archive index line %d: %w", i+1, err)
+		}
+
+		index = append(index, e)
+	}
+
+	sortIndex(index)
+
+	return index, false, nil
+}
+
+func sortIndex(index []entry) {
+	sort.SliceStable(index, func(i, j int) bool {
+		return index[i].Issued < index[j].Issued
+	})
+}
+
+// Archive implements seaweed.Archiver, appending the spot's forecasts to the
+// archive as a single issue. An issue already archived for the spot, as
+// identified by its most recent IssueTimestamp, is not archived again.
+func (a *Archive) Archive(spot string, forecasts []seaweed.Forecast) error {
+	if spot == "" {
+		return errors.New("archiving forecast: empty spot ID")
+	}
+
+	if len(forecasts) == 0 {
+		return nil
+	}
+
+	issue := Issue{
+		Spot:      spot,
+		FetchedAt: a.clock.Now().UTC(),
+		Forecasts: forecasts,
+	}
+
+	e := entry{
+		Spot:  spot,
+		Start: int64(forecasts[0].Timestamp),
+		End:   int64(forecasts[0].Timestamp),
+	}
+
+	for _, f := range forecasts {
+		if issued := int64(f.IssueTimestamp); issued > e.Issued {
+			e.Issued = issued
+		}
+
+		if ts := int64(f.Timestamp); ts < e.Start {
+			e.Start = ts
+		} else if ts > e.End {
+			e.End = ts
+		}
+	}
+
+	issue.IssuedAt = time.Unix(e.Issued, 0).UTC()
+	e.Segment = segmentPath(spot, issue.IssuedAt)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, existing := range a.index {
+		if existing.Spot == spot && existing.Issued == e.Issued {
+			return nil
+		}
+	}
+
+	member, err := encodeMember(issue, gzip.DefaultCompression)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(a.dir, e.Segment)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	e.Offset, err = appendFile(path, member)
+	if err != nil {
+		return err
+	}
+	e.Length = int64(len(member))
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if _, err := appendFile(filepath.Join(a.dir, indexFile), append(line, '\n')); err != nil {
+		return err
+	}
+
+	a.index = append(a.index, e)
+	sortIndex(a.index)
+
+	return nil
+}
+
+// segmentPath returns the path, relative to the archive directory, of the
+// segment holding the spot's issues issued on the UTC day of issued.
+func segmentPath(spot string, issued time.Time) string {
+	return filepath.Join(url.PathEscape(spot), issued.UTC().Format("2006-01-02")+segmentExt)
+}
+
+// encodeMember encodes issue as a JSON line within a gzip member, compressed
+// at the gzip level.
+func encodeMember(issue Issue, level int) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.NewEncoder(zw).Encode(issue); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// appendFile appends p to the file at path, creating it if need be, and
+// returns the offset at which p was written.
+func appendFile(path string, p []byte) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	if _, err := f.Write(p); err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	return info.Size(), f.Close()
+}
+
+// Covering returns the spot's archived issues forecasting the time t, ordered
+// by issue time, such as all issues for spot 391 covering 2026-10-18T06:00Z.
+func (a *Archive) Covering(spot string, t time.Time) ([]Issue, error) {
+	ts := t.Unix()
+
+	return a.query(func(e entry) bool {
+		return e.Spot == spot && e.Start <= ts && ts <= e.End
+	})
+}
+
+// Issued returns the spot's archived issues issued between from and to,
+// inclusive, ordered by issue time. A zero from or to leaves that end of the
+// range open.
+func (a *Archive) Issued(spot string, from, to time.Time) ([]Issue, error) {
+	return a.query(func(e entry) bool {
+		if e.Spot != spot {
+			return false
+		}
+
+		if !from.IsZero() && e.Issued < from.Unix() {
+			return false
+		}
+
+		return to.IsZero() || e.Issued <= to.Unix()
+	})
+}
+
+// Spots returns the IDs of the spots with archived issues, sorted.
+func (a *Archive) Spots() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seen := map[string]bool{}
+	var spots []string
+	for _, e := range a.index {
+		if !seen[e.Spot] {
+			seen[e.Spot] = true
+			spots = append(spots, e.Spot)
+		}
+	}
+	sort.Strings(spots)
+
+	return spots
+}
+
+// query returns the archived issues whose index records satisfy match.
+func (a *Archive) query(match func(entry) bool) ([]Issue, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var issues []Issue
+	for _, e := range a.index {
+		if !match(e) {
+			continue
+		}
+
+		issue, err := a.read(e)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// read returns the issue located by the index record e.
+func (a *Archive) read(e entry) (Issue, error) {
+	f, err := os.Open(filepath.Join(a.dir, e.Segment))
+	if err != nil {
+		return Issue{}, err
+	}
+	defer f.Close()
+
+	issues, err := decodeIssues(io.NewSectionReader(f, e.Offset, e.Length))
+	if err != nil {
+		return Issue{}, fmt.Errorf("reading archived issue from %s: %w", e.Segment, err)
+	}
+
+	for _, issue := range issues {
+		if issue.Spot == e.Spot && issue.IssuedAt.Unix() == e.Issued {
+			return issue, nil
+		}
+	}
+
+	return Issue{}, fmt.Errorf("issue %d of spot %s not found in %s", e.Issued, e.Spot, e.Segment)
+}
+
+// decodeIssues decodes the JSON lines of one or more concatenated gzip
+// members.
+func decodeIssues(r io.Reader) ([]Issue, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var issues []Issue
+	dec := json.NewDecoder(zr)
+	for {
+		var issue Issue
+		err := dec.Decode(&issue)
+		if errors.Is(err, io.EOF) {
+			return issues, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, issue)
+	}
+}
@@ -0,0 +1,246 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// issue returns forecasts issued at issued, covering every 3 hours of the
+// following day.
+func issue(issued time.Time) []seaweed.Forecast {
+	start := issued.Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	var forecasts []seaweed.Forecast
+	for i := 0; i < 8; i++ {
+		ts := start.Add(time.Duration(i) * 3 * time.Hour)
+		forecasts = append(forecasts, seaweed.Forecast{
+			Timestamp:      seaweed.FlexInt64(ts.Unix()),
+			LocalTimestamp: seaweed.FlexInt64(ts.Unix()),
+			IssueTimestamp: seaweed.FlexInt64(issued.Unix()),
+			SolidRating:    seaweed.FlexInt(i % 5),
+		})
+	}
+
+	return forecasts
+}
+
+func issueTimes(issues []Issue) []time.Time {
+	var times []time.Time
+	for _, issue := range issues {
+		times = append(times, issue.IssuedAt)
+	}
+
+	return times
+}
+
+var (
+	oct16  = time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	oct17  = time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	oct17b = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+)
+
+func openTestArchive(t *testing.T, dir string, opts ...Option) *Archive {
+	t.Helper()
+
+	a, err := Open(dir, append([]Option{WithClock(&testClock{now: oct17b})}, opts...)...)
+	if err != nil {
+		t.Fatalf("expected Open not to error; got '%v'", err)
+	}
+
+	return a
+}
+
+func archiveAll(t *testing.T, a *Archive, spot string, issued ...time.Time) {
+	t.Helper()
+
+	for _, i := range issued {
+		if err := a.Archive(spot, issue(i)); err != nil {
+			t.Fatalf("expected Archive not to error; got '%v'", err)
+		}
+	}
+}
+
+func TestCovering(t *testing.T) {
+	a := openTestArchive(t, t.TempDir())
+	archiveAll(t, a, "391", oct17b, oct16, oct17)
+	archiveAll(t, a, "392", oct17)
+
+	tests := []struct {
+		desc   string
+		spot   string
+		at     time.Time
+		expect []time.Time
+	}{{
+		desc:   "covered by every issue",
+		spot:   "391",
+		at:     time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC),
+		expect: []time.Time{oct17, oct17b},
+	}, {
+		desc:   "covered by one issue",
+		spot:   "391",
+		at:     time.Date(2026, 10, 17, 21, 0, 0, 0, time.UTC),
+		expect: []time.Time{oct16},
+	}, {
+		desc:   "between timesteps",
+		spot:   "391",
+		at:     time.Date(2026, 10, 17, 1, 30, 0, 0, time.UTC),
+		expect: []time.Time{oct16},
+	}, {
+		desc: "not covered",
+		spot: "391",
+		at:   time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+	}, {
+		desc:   "another spot",
+		spot:   "392",
+		at:     time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC),
+		expect: []time.Time{oct17},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			issues, err := a.Covering(test.spot, test.at)
+			if err != nil {
+				t.Fatalf("expected Covering not to error; got '%v'", err)
+			}
+
+			if got := issueTimes(issues); !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestIssued(t *testing.T) {
+	a := openTestArchive(t, t.TempDir())
+	archiveAll(t, a, "391", oct16, oct17, oct17b)
+
+	tests := []struct {
+		desc     string
+		from, to time.Time
+		expect   []time.Time
+	}{{
+		desc:   "open range",
+		expect: []time.Time{oct16, oct17, oct17b},
+	}, {
+		desc:   "inclusive range",
+		from:   oct17,
+		to:     oct17b,
+		expect: []time.Time{oct17, oct17b},
+	}, {
+		desc:   "open start",
+		to:     oct17,
+		expect: []time.Time{oct16, oct17},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			issues, err := a.Issued("391", test.from, test.to)
+			if err != nil {
+				t.Fatalf("expected Issued not to error; got '%v'", err)
+			}
+
+			if got := issueTimes(issues); !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestArchive(t, dir)
+
+	// Archiving the same issue again is a no-op.
+	archiveAll(t, a, "391", oct17, oct17, oct17b)
+
+	if err := a.Archive("", issue(oct17)); err == nil {
+		t.Error("expected archiving an empty spot ID to error")
+	}
+
+	if err := a.Archive("391", nil); err != nil {
+		t.Errorf("expected archiving no forecasts not to error; got '%v'", err)
+	}
+
+	// Both issues share a segment.
+	segments, err := filepath.Glob(filepath.Join(dir, "391", "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := []string{filepath.Join(dir, "391", "2026-10-17.jsonl.gz")}; !reflect.DeepEqual(segments, expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, segments)
+	}
+
+	reopened := openTestArchive(t, dir)
+
+	issues, err := reopened.Issued("391", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("expected Issued not to error; got '%v'", err)
+	}
+
+	if expect := []time.Time{oct17, oct17b}; !reflect.DeepEqual(issueTimes(issues), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, issueTimes(issues))
+	}
+
+	if !reflect.DeepEqual(issues[0].Forecasts, issue(oct17)) {
+		t.Errorf("expected archived forecasts to round trip; got '%v'", issues[0].Forecasts)
+	}
+
+	if !issues[0].FetchedAt.Equal(oct17b) {
+		t.Errorf("expected '%s'; got '%s'", oct17b, issues[0].FetchedAt)
+	}
+
+	if expect := []string{"391"}; !reflect.DeepEqual(reopened.Spots(), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, reopened.Spots())
+	}
+}
+
+func TestOpen_truncatedIndex(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestArchive(t, dir)
+	archiveAll(t, a, "391", oct16)
+
+	f, err := os.OpenFile(filepath.Join(dir, indexFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"spot":"391","iss`)
+	f.Close()
+
+	reopened := openTestArchive(t, dir)
+	archiveAll(t, reopened, "391", oct17)
+
+	reopened = openTestArchive(t, dir)
+	issues, err := reopened.Issued("391", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("expected Issued not to error; got '%v'", err)
+	}
+
+	if expect := []time.Time{oct16, oct17}; !reflect.DeepEqual(issueTimes(issues), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, issueTimes(issues))
+	}
+}
+
+func TestOpen_corruptIndex(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, indexFile), []byte("nope\n{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(dir); err == nil {
+		t.Error("expected Open to error")
+	}
+}
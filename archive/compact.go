@@ -0,0 +1,188 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mdb/seaweed/internal/atomicfile"
+)
+
+// Compact applies the archive's retention policy, deleting issues issued more
+// than its retention ago (see WithRetention), and rewrites the remaining
+// issues of each spot's day of issue into a single new segment, recompressing
+// each issue's gzip member at the best compression level.
+//
+// Compacted segments are written alongside the originals, under new names,
+// and the index is persisted before the originals are deleted, such that the
+// index never locates an issue within a rewritten file. If Compact fails, or
+// crashes, before persisting the index, the archive is left as it was, save
+// for unreferenced compacted segments.
+func (a *Archive) Compact() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var cutoff int64
+	if a.retention > 0 {
+		cutoff = a.clock.Now().Add(-a.retention).Unix()
+	}
+
+	// Group the index by spot and day of issue, preserving issue order within
+	// each, as a day's issues may span a compacted segment and the segment
+	// appended to since.
+	days := map[string][]entry{}
+	var order []string
+	for _, e := range a.index {
+		day := segmentPath(e.Spot, time.Unix(e.Issued, 0))
+		if _, ok := days[day]; !ok {
+			order = append(order, day)
+		}
+		days[day] = append(days[day], e)
+	}
+
+	var index []entry
+	var written, obsolete []string
+	seen := map[string]bool{}
+	for _, day := range order {
+		var retained []entry
+		for _, e := range days[day] {
+			if !seen[e.Segment] {
+				seen[e.Segment] = true
+				obsolete = append(obsolete, e.Segment)
+			}
+
+			if e.Issued >= cutoff {
+				retained = append(retained, e)
+			}
+		}
+
+		if len(retained) == 0 {
+			continue
+		}
+
+		segment, rewritten, err := a.rewriteSegment(day, retained)
+		if err != nil {
+			a.removeSegments(written)
+			return err
+		}
+
+		written = append(written, segment)
+		index = append(index, rewritten...)
+	}
+
+	sortIndex(index)
+	previous := a.index
+	a.index = index
+
+	if err := a.writeIndex(); err != nil {
+		a.index = previous
+		a.removeSegments(written)
+		return err
+	}
+
+	return a.removeSegments(obsolete)
+}
+
+// rewriteSegment writes the issues of the retained index records to a new
+// segment named for day, each in its own gzip member, and returns the new
+// segment's path, relative to the archive directory, and the records updated
+// to locate the issues within it.
+func (a *Archive) rewriteSegment(day string, retained []entry) (string, []entry, error) {
+	segment, err := a.compactedPath(day)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	rewritten := make([]entry, len(retained))
+	for i, e := range retained {
+		issue, err := a.read(e)
+		if err != nil {
+			return "", nil, err
+		}
+
+		member, err := encodeMember(issue, gzip.BestCompression)
+		if err != nil {
+			return "", nil, err
+		}
+
+		e.Segment = segment
+		e.Offset = int64(buf.Len())
+		e.Length = int64(len(member))
+		rewritten[i] = e
+
+		buf.Write(member)
+	}
+
+	if err := atomicfile.WriteFile(filepath.Join(a.dir, segment), buf.Bytes()); err != nil {
+		return "", nil, err
+	}
+
+	return segment, rewritten, nil
+}
+
+// compactedPath returns an unused path, relative to the archive directory,
+// for a compaction of day's segment, such as 391/2026-10-18.1.jsonl.gz.
+func (a *Archive) compactedPath(day string) (string, error) {
+	stem := strings.TrimSuffix(day, segmentExt)
+	for n := 1; ; n++ {
+		segment := fmt.Sprintf("%s.%d%s", stem, n, segmentExt)
+
+		_, err := os.Stat(filepath.Join(a.dir, segment))
+		if errors.Is(err, fs.ErrNotExist) {
+			return segment, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// removeSegments deletes the segments, returning the first error encountered.
+func (a *Archive) removeSegments(segments []string) error {
+	var first error
+	for _, segment := range segments {
+		if err := a.removeSegment(segment); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// removeSegment deletes the segment, as well as its spot's directory if it
+// is left empty.
+func (a *Archive) removeSegment(segment string) error {
+	path := filepath.Join(a.dir, segment)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) > 0 {
+		return nil
+	}
+
+	return os.Remove(filepath.Dir(path))
+}
+
+// writeIndex atomically rewrites the index file from the in-memory index.
+func (a *Archive) writeIndex() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range a.index {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	return atomicfile.WriteFile(filepath.Join(a.dir, indexFile), buf.Bytes())
+}
@@ -0,0 +1,179 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		desc      string
+		retention time.Duration
+		expect    []time.Time
+		removed   bool
+	}{{
+		desc:   "no retention",
+		expect: []time.Time{oct16, oct17, oct17b},
+	}, {
+		desc:      "retention",
+		retention: 24 * time.Hour,
+		expect:    []time.Time{oct17, oct17b},
+		removed:   true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			a := openTestArchive(t, dir, WithRetention(test.retention))
+			archiveAll(t, a, "391", oct16, oct17, oct17b)
+
+			segment := filepath.Join(dir, "391", "2026-10-17.jsonl.gz")
+			before, err := os.Stat(segment)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := a.Compact(); err != nil {
+				t.Fatalf("expected Compact not to error; got '%v'", err)
+			}
+
+			if _, err := os.Stat(segment); !os.IsNotExist(err) {
+				t.Errorf("expected the original segment to be removed; got '%v'", err)
+			}
+
+			after, err := os.Stat(filepath.Join(dir, "391", "2026-10-17.1.jsonl.gz"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if after.Size() > before.Size() {
+				t.Errorf("expected compacted segment not to grow from '%d' bytes; got '%d'", before.Size(), after.Size())
+			}
+
+			// Each of the day's two issues is located within its own gzip member.
+			for _, e := range a.index {
+				if e.Issued >= oct17.Unix() && e.Length >= after.Size() {
+					t.Errorf("expected issue '%d' to span a single member; got '%d' of '%d' bytes", e.Issued, e.Length, after.Size())
+				}
+			}
+
+			expired, err := filepath.Glob(filepath.Join(dir, "391", "2026-10-16*"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if removed := len(expired) == 0; removed != test.removed {
+				t.Errorf("expected expired segment removal to be '%t'; got '%t'", test.removed, removed)
+			}
+
+			// Archiving continues to append to compacted segments.
+			later := oct17b.Add(3 * time.Hour)
+			archiveAll(t, a, "391", later)
+
+			for _, archive := range []*Archive{a, openTestArchive(t, dir)} {
+				issues, err := archive.Issued("391", time.Time{}, time.Time{})
+				if err != nil {
+					t.Fatalf("expected Issued not to error; got '%v'", err)
+				}
+
+				expect := append(append([]time.Time{}, test.expect...), later)
+				if got := issueTimes(issues); !reflect.DeepEqual(got, expect) {
+					t.Errorf("expected '%v'; got '%v'", expect, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompact_removesEmptySpots(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestArchive(t, dir, WithRetention(time.Hour))
+	archiveAll(t, a, "391", oct16)
+
+	if err := a.Compact(); err != nil {
+		t.Fatalf("expected Compact not to error; got '%v'", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "391")); !os.IsNotExist(err) {
+		t.Errorf("expected spot directory to be removed; got '%v'", err)
+	}
+
+	if spots := a.Spots(); len(spots) != 0 {
+		t.Errorf("expected no spots; got '%v'", spots)
+	}
+}
+
+func TestCompact_repeated(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestArchive(t, dir)
+	archiveAll(t, a, "391", oct17)
+
+	if err := a.Compact(); err != nil {
+		t.Fatalf("expected Compact not to error; got '%v'", err)
+	}
+
+	// The day's compacted segment and the segment appended to since are
+	// compacted together.
+	archiveAll(t, a, "391", oct17b)
+
+	if err := a.Compact(); err != nil {
+		t.Fatalf("expected Compact not to error; got '%v'", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "391"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	if expect := []string{"2026-10-17.2.jsonl.gz"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, names)
+	}
+
+	issues, err := openTestArchive(t, dir).Issued("391", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("expected Issued not to error; got '%v'", err)
+	}
+
+	if expect, got := []time.Time{oct17, oct17b}, issueTimes(issues); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, got)
+	}
+}
+
+func TestCompact_failure(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestArchive(t, dir)
+	archiveAll(t, a, "391", oct16, oct17)
+
+	// The Oct 16th segment is rewritten before the Oct 17th segment fails to
+	// be read.
+	if err := os.WriteFile(filepath.Join(dir, "391", "2026-10-17.jsonl.gz"), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Compact(); err == nil {
+		t.Fatal("expected Compact to error")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "391", "2026-10-16.1.jsonl.gz")); !os.IsNotExist(err) {
+		t.Errorf("expected the unreferenced compacted segment to be removed; got '%v'", err)
+	}
+
+	for _, archive := range []*Archive{a, openTestArchive(t, dir)} {
+		issues, err := archive.Issued("391", oct16, oct16)
+		if err != nil {
+			t.Fatalf("expected Issued not to error; got '%v'", err)
+		}
+
+		if expect, got := []time.Time{oct16}, issueTimes(issues); !reflect.DeepEqual(got, expect) {
+			t.Errorf("expected '%v'; got '%v'", expect, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package seaweed
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+type testArchiver struct {
+	spots []string
+	err   error
+}
+
+func (a *testArchiver) Archive(spot string, forecasts []Forecast) error {
+	a.spots = append(a.spots, spot)
+
+	return a.err
+}
+
+func TestWithArchive(t *testing.T) {
+	tests := []struct {
+		desc string
+		err  error
+	}{{
+		desc: "archiving succeeds",
+	}, {
+		desc: "archiving fails",
+		err:  errors.New("disk full"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var failing atomic.Bool
+			var revalidated atomic.Int32
+			archiver := &testArchiver{err: test.err}
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			server, c := flakyServerAndClient(&failing, &revalidated, WithArchive(archiver), WithLogger(logger))
+			defer server.Close()
+
+			// The second fetch is revalidated via a 304 and is not re-archived.
+			for i := 0; i < 2; i++ {
+				forecasts, err := c.Forecast("123")
+				if err != nil {
+					t.Fatalf("expected Forecast not to error; got '%v'", err)
+				}
+
+				if len(forecasts) != 3 {
+					t.Errorf("expected '3' forecasts; got '%d'", len(forecasts))
+				}
+			}
+
+			if len(archiver.spots) != 1 || archiver.spots[0] != "123" {
+				t.Errorf("expected '[123]' to be archived; got '%v'", archiver.spots)
+			}
+		})
+	}
+}
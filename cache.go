@@ -0,0 +1,145 @@
+package seaweed
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StaleError is returned alongside a spot's last successfully fetched
+// forecasts when fetching fresh forecasts fails and the Client is configured
+// WithStaleIfError.
+type StaleError struct {
+	// Err is the error encountered fetching fresh forecasts.
+	Err error
+	// FetchedAt is the time at which the stale forecasts were last
+	// successfully fetched or revalidated.
+	FetchedAt time.Time
+	// IssuedAt is the most recent IssueTimestamp among the stale forecasts.
+	IssuedAt time.Time
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("serving stale forecast issued %s: %s", e.IssuedAt.UTC().Format(time.RFC3339), e.Err)
+}
+
+// Unwrap returns the error encountered fetching fresh forecasts.
+func (e *StaleError) Unwrap() error {
+	return e.Err
+}
+
+// IssueAge returns how old the stale forecasts' issue is relative to now.
+func (e *StaleError) IssueAge(now time.Time) time.Duration {
+	return now.Sub(e.IssuedAt)
+}
+
+// IsStale returns true if err is or wraps a *StaleError, in which case the
+// forecasts returned alongside it are stale but usable.
+func IsStale(err error) bool {
+	var stale *StaleError
+
+	return errors.As(err, &stale)
+}
+
+// cachedForecast is a spot's last successfully fetched forecasts.
+type cachedForecast struct {
+	data      []Forecast
+	fetchedAt time.Time
+}
+
+// store caches a spot's successfully fetched forecasts.
+func (c *Client) store(spot string, forecasts []Forecast) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache.Add(spot, &cachedForecast{
+		data:      forecasts,
+		fetchedAt: c.clock.Now(),
+	})
+}
+
+// cached returns a copy of the spot's cached forecasts, and false if there are
+// none.
+func (c *Client) cached(spot string) ([]Forecast, bool) {
+	c.mu.Lock()
+	cf, ok := c.cache.Get(spot)
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return copyForecasts(cf.data), true
+}
+
+// isNewIssue returns true if forecasts are of a different issue than the
+// spot's cached forecasts, as identified by their most recent IssueTimestamp.
+func (c *Client) isNewIssue(spot string, forecasts []Forecast) bool {
+	c.mu.Lock()
+	cf, ok := c.cache.Get(spot)
+	c.mu.Unlock()
+
+	return !ok || latestIssue(cf.data) != latestIssue(forecasts)
+}
+
+// stale returns a copy of the spot's cached forecasts and a *StaleError
+// wrapping err if the Client is configured WithStaleIfError and the cached
+// forecasts are no older than its maxStale. Otherwise it returns a nil error,
+// dropping cached forecasts too old to serve.
+func (c *Client) stale(spot string, err error) ([]Forecast, error) {
+	if c.maxStale <= 0 {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	cf, ok := c.cache.Get(spot)
+	if ok && c.clock.Now().Sub(cf.fetchedAt) > c.maxStale {
+		c.cache.Remove(spot)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return copyForecasts(cf.data), &StaleError{
+		Err:       err,
+		FetchedAt: cf.fetchedAt,
+		IssuedAt:  time.Unix(latestIssue(cf.data), 0).UTC(),
+	}
+}
+
+// latestIssue returns the most recent IssueTimestamp among forecasts.
+func latestIssue(forecasts []Forecast) int64 {
+	var issued int64
+	for _, f := range forecasts {
+		if int64(f.IssueTimestamp) > issued {
+			issued = int64(f.IssueTimestamp)
+		}
+	}
+
+	return issued
+}
+
+// copyForecasts returns a deep copy of forecasts, such that modifying the
+// copy, including its optional swell components, leaves forecasts unchanged.
+func copyForecasts(forecasts []Forecast) []Forecast {
+	c := make([]Forecast, len(forecasts))
+	copy(c, forecasts)
+
+	for i := range c {
+		components := &c[i].Swell.Components
+		if components.Secondary != nil {
+			secondary := *components.Secondary
+			components.Secondary = &secondary
+		}
+
+		if components.Tertiary != nil {
+			tertiary := *components.Tertiary
+			components.Tertiary = &tertiary
+		}
+	}
+
+	return c
+}
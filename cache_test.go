@@ -0,0 +1,248 @@
+package seaweed
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServerAndClient returns a test server that serves resp with an ETag,
+// responds 304 to requests revalidating that ETag, and responds with a 500
+// while failing is set.
+func flakyServerAndClient(failing *atomic.Bool, revalidated *atomic.Int32, opts ...ClientOption) (*httptest.Server, *Client) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case failing.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Tue, 15 Sep 2015 22:15:56 GMT")
+			fmt.Fprint(w, resp)
+		}
+	}))
+
+	client := NewClient(
+		"fakeKey",
+		append([]ClientOption{
+			WithBaseURL(server.URL),
+			WithHTTPClient(server.Client()),
+			WithClock(testClock{}),
+		}, opts...)...,
+	)
+
+	return server, client
+}
+
+func TestForecast_revalidation(t *testing.T) {
+	var failing atomic.Bool
+	var revalidated atomic.Int32
+	server, c := flakyServerAndClient(&failing, &revalidated)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		forecasts, err := c.Forecast("123")
+		if err != nil {
+			t.Fatalf("expected Forecast not to error; got '%v'", err)
+		}
+
+		if len(forecasts) != 3 {
+			t.Errorf("expected '3' forecasts; got '%d'", len(forecasts))
+		}
+	}
+
+	if revalidated.Load() != 1 {
+		t.Errorf("expected the second request to be revalidated; got '%d' revalidations", revalidated.Load())
+	}
+
+	failing.Store(true)
+
+	forecasts, err := c.Forecast("123")
+	if err == nil || IsStale(err) {
+		t.Errorf("expected a non-stale error without WithStaleIfError; got '%v'", err)
+	}
+
+	if len(forecasts) != 0 {
+		t.Errorf("expected '0' forecasts; got '%d'", len(forecasts))
+	}
+}
+
+func TestForecast_revalidation_eviction(t *testing.T) {
+	var failing atomic.Bool
+	var revalidated atomic.Int32
+	server, c := flakyServerAndClient(&failing, &revalidated, WithMaxCachedSpots(1))
+	defer server.Close()
+
+	for _, spot := range []string{"123", "456", "123", "123"} {
+		forecasts, err := c.Forecast(spot)
+		if err != nil {
+			t.Fatalf("expected Forecast not to error; got '%v'", err)
+		}
+
+		if len(forecasts) != 3 {
+			t.Errorf("expected '3' forecasts; got '%d'", len(forecasts))
+		}
+	}
+
+	// The evicted spot is refetched rather than revalidated.
+	if revalidated.Load() != 1 {
+		t.Errorf("expected only the last request to be revalidated; got '%d' revalidations", revalidated.Load())
+	}
+}
+
+func TestForecast_unconditionalNotModified(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	c := NewClient("fakeKey", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+
+	forecasts, err := c.Forecast("123")
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotModified {
+		t.Errorf("expected an *HTTPError with status code 304; got '%v'", err)
+	}
+
+	if len(forecasts) != 0 {
+		t.Errorf("expected '0' forecasts; got '%d'", len(forecasts))
+	}
+}
+
+func TestCopyForecasts(t *testing.T) {
+	forecasts := []Forecast{{}}
+	forecasts[0].Swell.Components.Secondary = &Component{Height: 2}
+	forecasts[0].Swell.Components.Tertiary = &Component{Height: 1}
+
+	c := copyForecasts(forecasts)
+	c[0].Swell.Components.Secondary.Height = 5
+	c[0].Swell.Components.Tertiary.Height = 5
+
+	if h := forecasts[0].Swell.Components.Secondary.Height; h != 2 {
+		t.Errorf("expected the original secondary height '2'; got '%v'", h)
+	}
+
+	if h := forecasts[0].Swell.Components.Tertiary.Height; h != 1 {
+		t.Errorf("expected the original tertiary height '1'; got '%v'", h)
+	}
+}
+
+func TestForecast_staleIfError(t *testing.T) {
+	var failing atomic.Bool
+	var revalidated atomic.Int32
+	server, c := flakyServerAndClient(&failing, &revalidated, WithStaleIfError(time.Hour))
+	defer server.Close()
+
+	failing.Store(true)
+
+	if _, err := c.Forecast("123"); err == nil || IsStale(err) {
+		t.Errorf("expected a non-stale error when nothing is cached; got '%v'", err)
+	}
+
+	failing.Store(false)
+
+	if _, err := c.Forecast("123"); err != nil {
+		t.Fatalf("expected Forecast not to error; got '%v'", err)
+	}
+
+	failing.Store(true)
+
+	forecasts, err := c.Forecast("123")
+
+	var stale *StaleError
+	if !errors.As(err, &stale) {
+		t.Fatalf("expected a *StaleError; got '%v'", err)
+	}
+
+	if len(forecasts) != 3 {
+		t.Errorf("expected '3' stale forecasts; got '%d'", len(forecasts))
+	}
+
+	expected := "GET /api/<REDACTED>/forecast/?spot_id=123 returned HTTP status code 500"
+	if stale.Unwrap().Error() != expected {
+		t.Errorf("expected stale error to wrap '%s'; got '%v'", expected, stale.Unwrap())
+	}
+
+	if !stale.IssuedAt.Equal(time.Unix(1677973254, 0)) {
+		t.Errorf("expected IssuedAt '%s'; got '%s'", time.Unix(1677973254, 0).UTC(), stale.IssuedAt)
+	}
+
+	if age := stale.IssueAge(time.Unix(1677973254, 0).Add(time.Hour)); age != time.Hour {
+		t.Errorf("expected IssueAge '%s'; got '%s'", time.Hour, age)
+	}
+
+	today, err := c.Today("123")
+	if !IsStale(err) {
+		t.Errorf("expected Today to return a stale error; got '%v'", err)
+	}
+
+	if len(today) != 1 {
+		t.Errorf("expected Today to return '1' stale forecast; got '%d'", len(today))
+	}
+
+	if _, err := c.Forecast("456"); err == nil || IsStale(err) {
+		t.Errorf("expected a non-stale error for an uncached spot; got '%v'", err)
+	}
+}
+
+type advancingClock struct {
+	now atomic.Int64
+}
+
+func (c *advancingClock) Now() time.Time {
+	return time.Unix(c.now.Load(), 0)
+}
+
+func TestForecast_staleIfError_eviction(t *testing.T) {
+	var failing atomic.Bool
+	var revalidated atomic.Int32
+	server, c := flakyServerAndClient(&failing, &revalidated, WithStaleIfError(time.Hour), WithMaxCachedSpots(1))
+	defer server.Close()
+
+	for _, spot := range []string{"123", "456"} {
+		if _, err := c.Forecast(spot); err != nil {
+			t.Fatalf("expected Forecast not to error; got '%v'", err)
+		}
+	}
+
+	failing.Store(true)
+
+	if _, err := c.Forecast("456"); !IsStale(err) {
+		t.Errorf("expected a stale error for the most recently fetched spot; got '%v'", err)
+	}
+
+	if _, err := c.Forecast("123"); err == nil || IsStale(err) {
+		t.Errorf("expected a non-stale error for an evicted spot; got '%v'", err)
+	}
+}
+
+func TestForecast_staleIfError_maxStale(t *testing.T) {
+	var failing atomic.Bool
+	var revalidated atomic.Int32
+	clock := &advancingClock{}
+	server, c := flakyServerAndClient(&failing, &revalidated, WithStaleIfError(time.Hour), WithClock(clock))
+	defer server.Close()
+
+	if _, err := c.Forecast("123"); err != nil {
+		t.Fatalf("expected Forecast not to error; got '%v'", err)
+	}
+
+	failing.Store(true)
+	clock.now.Store(int64((2 * time.Hour).Seconds()))
+
+	forecasts, err := c.Forecast("123")
+	if err == nil || IsStale(err) {
+		t.Errorf("expected a non-stale error once the cache exceeds maxStale; got '%v'", err)
+	}
+
+	if len(forecasts) != 0 {
+		t.Errorf("expected '0' forecasts; got '%d'", len(forecasts))
+	}
+}
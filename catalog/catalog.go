@@ -0,0 +1,188 @@
+// Package catalog is a catalog of Magic Seaweed spots, searchable by name and
+// by proximity to a coordinate, such that spots need not be referenced by
+// their numeric IDs.
+//
+// A default catalog is embedded in the package. It may be extended or
+// corrected by merging in spots loaded from a JSON file of the same format;
+// see Catalog.Merge and ReadFile.
+package catalog
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/internal/atomicfile"
+)
+
+//go:embed spots.json
+var embedded []byte
+
+// Spot describes a Magic Seaweed spot.
+type Spot struct {
+	// ID is the spot's Magic Seaweed spot ID.
+	ID seaweed.SpotID `json:"id"`
+	// Name is the spot's name, such as "Ocean City, NJ".
+	Name string `json:"name"`
+	// Region is the spot's region, such as "South Jersey".
+	Region string `json:"region"`
+	// Country is the spot's country.
+	Country string `json:"country"`
+	// Lat is the spot's latitude, in decimal degrees.
+	Lat float64 `json:"lat"`
+	// Lon is the spot's longitude, in decimal degrees.
+	Lon float64 `json:"lon"`
+	// Orientation is the direction the spot's beach faces, in degrees
+	// clockwise from north.
+	Orientation float64 `json:"orientation"`
+}
+
+// Catalog is a set of spots, keyed by ID.
+type Catalog struct {
+	spots map[seaweed.SpotID]Spot
+}
+
+// New returns a *Catalog of the spots it's passed.
+func New(spots ...Spot) *Catalog {
+	c := &Catalog{spots: map[seaweed.SpotID]Spot{}}
+	c.Merge(spots...)
+
+	return c
+}
+
+// Default returns a *Catalog of the spots embedded in the package.
+func Default() *Catalog {
+	c, err := Load(bytes.NewReader(embedded))
+	if err != nil {
+		panic(fmt.Sprintf("catalog: invalid embedded spots: %s", err))
+	}
+
+	return c
+}
+
+// Load returns a *Catalog of the JSON array of spots read from r.
+func Load(r io.Reader) (*Catalog, error) {
+	var spots []Spot
+	if err := json.NewDecoder(r).Decode(&spots); err != nil {
+		return nil, fmt.Errorf("decoding spot catalog: %w", err)
+	}
+
+	for i, s := range spots {
+		if s.ID == "" {
+			return nil, fmt.Errorf("decoding spot catalog: spot %d has no ID", i)
+		}
+	}
+
+	return New(spots...), nil
+}
+
+// ReadFile returns a *Catalog of the JSON array of spots in the file at path.
+func ReadFile(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Merge adds the spots it's passed to the catalog, replacing any spots of the
+// same ID.
+func (c *Catalog) Merge(spots ...Spot) {
+	for _, s := range spots {
+		c.spots[s.ID] = s
+	}
+}
+
+// Spots returns the catalog's spots, ordered by name.
+func (c *Catalog) Spots() []Spot {
+	spots := make([]Spot, 0, len(c.spots))
+	for _, s := range c.spots {
+		spots = append(spots, s)
+	}
+
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].Name != spots[j].Name {
+			return spots[i].Name < spots[j].Name
+		}
+
+		return spots[i].ID < spots[j].ID
+	})
+
+	return spots
+}
+
+// Get returns the spot with the given ID, and false if there is none.
+func (c *Catalog) Get(id seaweed.SpotID) (Spot, bool) {
+	s, ok := c.spots[id]
+
+	return s, ok
+}
+
+// Write writes the catalog's spots to w as a JSON array, in the format read by
+// Load.
+func (c *Catalog) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(c.Spots())
+}
+
+// WriteFile atomically writes the catalog to the file at path, in the format
+// read by ReadFile.
+func (c *Catalog) WriteFile(path string) error {
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(path, buf.Bytes())
+}
+
+// Neighbor is a spot and its distance from a coordinate.
+type Neighbor struct {
+	Spot Spot
+	// Distance is the great-circle distance to the spot, in kilometers.
+	Distance float64
+}
+
+// earthRadius is the Earth's mean radius, in kilometers.
+const earthRadius = 6371.0
+
+// Nearest returns the n spots nearest the coordinate, nearest first.
+func (c *Catalog) Nearest(lat, lon float64, n int) []Neighbor {
+	neighbors := make([]Neighbor, 0, len(c.spots))
+	for _, s := range c.Spots() {
+		neighbors = append(neighbors, Neighbor{Spot: s, Distance: distance(lat, lon, s.Lat, s.Lon)})
+	}
+
+	sort.SliceStable(neighbors, func(i, j int) bool {
+		return neighbors[i].Distance < neighbors[j].Distance
+	})
+
+	if n >= 0 && n < len(neighbors) {
+		neighbors = neighbors[:n]
+	}
+
+	return neighbors
+}
+
+// distance returns the haversine great-circle distance between two
+// coordinates, in kilometers.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
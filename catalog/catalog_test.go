@@ -0,0 +1,160 @@
+package catalog
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+func testCatalog() *Catalog {
+	return New(
+		Spot{ID: "391", Name: "Ocean City, NJ", Region: "South Jersey", Lat: 39.277, Lon: -74.574},
+		Spot{ID: "392", Name: "Ocean City, MD", Region: "Maryland", Lat: 38.336, Lon: -75.084},
+		Spot{ID: "386", Name: "Manasquan Inlet", Region: "Central Jersey", Lat: 40.103, Lon: -74.033},
+		Spot{ID: "616", Name: "Pipeline", Region: "Oahu", Lat: 21.665, Lon: -158.053},
+	)
+}
+
+func TestDefault(t *testing.T) {
+	c := Default()
+
+	s, ok := c.Get("391")
+	if !ok {
+		t.Fatal("expected the default catalog to include spot 391")
+	}
+
+	if s.Name != "Ocean City, NJ" {
+		t.Errorf("expected 'Ocean City, NJ'; got '%s'", s.Name)
+	}
+
+	for _, s := range c.Spots() {
+		if _, err := seaweed.ParseSpotURL("magicseaweed.com/Spot-Surf-Report/" + string(s.ID)); err != nil {
+			t.Errorf("expected spot %q to have a valid ID; got '%v'", s.Name, err)
+		}
+
+		if s.Lat < -90 || s.Lat > 90 || s.Lon < -180 || s.Lon > 180 {
+			t.Errorf("expected spot %q to have a valid coordinate; got '%g,%g'", s.Name, s.Lat, s.Lon)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		desc      string
+		json      string
+		expect    []Spot
+		expectErr string
+	}{{
+		desc:   "spots",
+		json:   `[{"id":"391","name":"Ocean City, NJ","orientation":125}]`,
+		expect: []Spot{{ID: "391", Name: "Ocean City, NJ", Orientation: 125}},
+	}, {
+		desc:      "a spot without an ID",
+		json:      `[{"name":"Nowhere"}]`,
+		expectErr: "spot 0 has no ID",
+	}, {
+		desc:      "invalid JSON",
+		json:      `{`,
+		expectErr: "decoding spot catalog",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			c, err := Load(strings.NewReader(test.json))
+			if test.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Errorf("expected error containing '%s'; got '%v'", test.expectErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected Load not to error; got '%v'", err)
+			}
+
+			if !reflect.DeepEqual(c.Spots(), test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, c.Spots())
+			}
+		})
+	}
+}
+
+func TestMergeAndWriteFile(t *testing.T) {
+	c := testCatalog()
+	c.Merge(Spot{ID: "391", Name: "Ocean City"}, Spot{ID: "1", Name: "Fistral North"})
+
+	path := filepath.Join(t.TempDir(), "spots.json")
+	if err := c.WriteFile(path); err != nil {
+		t.Fatalf("expected WriteFile not to error; got '%v'", err)
+	}
+
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected ReadFile not to error; got '%v'", err)
+	}
+
+	if !reflect.DeepEqual(read.Spots(), c.Spots()) {
+		t.Errorf("expected '%v'; got '%v'", c.Spots(), read.Spots())
+	}
+
+	if s, _ := read.Get("391"); s.Name != "Ocean City" {
+		t.Errorf("expected merged spot to replace the original; got '%v'", s)
+	}
+
+	if len(read.Spots()) != 5 {
+		t.Errorf("expected '5' spots; got '%d'", len(read.Spots()))
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lat, lon float64
+		n        int
+		expect   []seaweed.SpotID
+	}{{
+		desc:   "Atlantic City",
+		lat:    39.364,
+		lon:    -74.423,
+		n:      2,
+		expect: []seaweed.SpotID{"391", "386"},
+	}, {
+		desc:   "Honolulu",
+		lat:    21.307,
+		lon:    -157.858,
+		n:      1,
+		expect: []seaweed.SpotID{"616"},
+	}, {
+		desc:   "all",
+		lat:    38.336,
+		lon:    -75.084,
+		n:      -1,
+		expect: []seaweed.SpotID{"392", "391", "386", "616"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var got []seaweed.SpotID
+			for _, n := range testCatalog().Nearest(test.lat, test.lon, test.n) {
+				got = append(got, n.Spot.ID)
+			}
+
+			if !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Ocean City, NJ to Ocean City, MD is roughly 115km.
+	got := distance(39.277, -74.574, 38.336, -75.084)
+	if math.Abs(got-115) > 5 {
+		t.Errorf("expected roughly '115'km; got '%g'", got)
+	}
+}
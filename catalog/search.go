@@ -0,0 +1,214 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mdb/seaweed"
+)
+
+// minScore is the minimum score of a search match.
+const minScore = 0.4
+
+// ErrNotFound is returned by Find when no spot matches a query.
+var ErrNotFound = errors.New("no matching spot")
+
+// Match is a spot matching a search query.
+type Match struct {
+	Spot Spot
+	// Score ranks the match, from 1 for an exact name match down to minScore
+	// for a loose fuzzy match.
+	Score float64
+}
+
+// Search returns up to limit spots whose name, or name and region, match the
+// query, best match first. Matching is case and punctuation insensitive, and
+// tolerates typos. A negative limit returns all matches.
+func (c *Catalog) Search(query string, limit int) []Match {
+	q := normalize(query)
+	if q == "" {
+		return nil
+	}
+
+	var matches []Match
+	for _, s := range c.Spots() {
+		score := matchScore(q, normalize(s.Name))
+		if withRegion := matchScore(q, normalize(s.Name+" "+s.Region)); withRegion > score {
+			score = withRegion
+		}
+
+		if score >= minScore {
+			matches = append(matches, Match{Spot: s, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	if limit >= 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// Find returns the spot a query references: a numeric spot ID, a Magic
+// Seaweed surf report URL (see seaweed.ParseSpotURL), or a spot name, in which
+// case the best Search match is returned. Spots referenced by an ID or URL
+// absent from the catalog are returned with only their ID set.
+func (c *Catalog) Find(query string) (Spot, error) {
+	query = strings.TrimSpace(query)
+
+	id := seaweed.SpotID(query)
+	if _, err := strconv.ParseUint(query, 10, 64); err != nil {
+		id, err = seaweed.ParseSpotURL(query)
+		if err != nil {
+			id = ""
+		}
+	}
+
+	if id != "" {
+		if s, ok := c.Get(id); ok {
+			return s, nil
+		}
+
+		return Spot{ID: id}, nil
+	}
+
+	matches := c.Search(query, 1)
+	if len(matches) == 0 {
+		return Spot{}, fmt.Errorf("%w: %q", ErrNotFound, query)
+	}
+
+	return matches[0].Spot, nil
+}
+
+// matchScore scores how well the normalized query q matches the normalized
+// text s.
+func matchScore(q, s string) float64 {
+	switch {
+	case q == s:
+		return 1
+	case strings.HasPrefix(s, q):
+		return 0.9
+	case tokensPrefix(strings.Fields(q), strings.Fields(s)):
+		return 0.8
+	case strings.Contains(s, q):
+		return 0.7
+	default:
+		return 0.6 * tokenSimilarity(strings.Fields(q), strings.Fields(s))
+	}
+}
+
+// tokensPrefix returns true if each query token prefixes a distinct text
+// token.
+func tokensPrefix(query, text []string) bool {
+	used := make([]bool, len(text))
+
+	for _, q := range query {
+		found := false
+		for i, t := range text {
+			if !used[i] && strings.HasPrefix(t, q) {
+				used[i] = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tokenSimilarity returns the mean, over the query tokens, of each token's
+// similarity to its most similar text token.
+func tokenSimilarity(query, text []string) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, q := range query {
+		var best float64
+		for _, t := range text {
+			if sim := similarity(q, t); sim > best {
+				best = sim
+			}
+		}
+
+		total += best
+	}
+
+	return total / float64(len(query))
+}
+
+// similarity returns 1 less the edit distance between a and b relative to the
+// longer of the two.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(n int, ns ...int) int {
+	for _, m := range ns {
+		if m < n {
+			n = m
+		}
+	}
+
+	return n
+}
+
+// normalize lower-cases s and collapses its punctuation and whitespace into
+// single spaces.
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
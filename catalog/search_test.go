@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		desc   string
+		query  string
+		limit  int
+		expect []seaweed.SpotID
+	}{{
+		desc:   "exact name",
+		query:  "Pipeline",
+		limit:  -1,
+		expect: []seaweed.SpotID{"616"},
+	}, {
+		desc:   "case and punctuation insensitive prefix",
+		query:  "ocean city",
+		limit:  -1,
+		expect: []seaweed.SpotID{"392", "391"},
+	}, {
+		desc:   "name and region",
+		query:  "ocean city south jersey",
+		limit:  -1,
+		expect: []seaweed.SpotID{"391"},
+	}, {
+		desc:   "token prefixes",
+		query:  "oc nj",
+		limit:  -1,
+		expect: []seaweed.SpotID{"391"},
+	}, {
+		desc:   "typos",
+		query:  "manasqan inlt",
+		limit:  -1,
+		expect: []seaweed.SpotID{"386"},
+	}, {
+		desc:   "limit",
+		query:  "ocean city",
+		limit:  1,
+		expect: []seaweed.SpotID{"392"},
+	}, {
+		desc:  "no match",
+		query: "zzzz",
+		limit: -1,
+	}, {
+		desc:  "empty query",
+		query: "  ",
+		limit: -1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var got []seaweed.SpotID
+			for _, m := range testCatalog().Search(test.query, test.limit) {
+				got = append(got, m.Spot.ID)
+			}
+
+			if !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		desc      string
+		query     string
+		expect    Spot
+		expectErr error
+	}{{
+		desc:   "ID",
+		query:  "616",
+		expect: Spot{ID: "616", Name: "Pipeline", Region: "Oahu", Lat: 21.665, Lon: -158.053},
+	}, {
+		desc:   "uncataloged ID",
+		query:  "1234",
+		expect: Spot{ID: "1234"},
+	}, {
+		desc:   "URL",
+		query:  "https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/",
+		expect: Spot{ID: "391", Name: "Ocean City, NJ", Region: "South Jersey", Lat: 39.277, Lon: -74.574},
+	}, {
+		desc:   "name",
+		query:  "ocean city nj",
+		expect: Spot{ID: "391", Name: "Ocean City, NJ", Region: "South Jersey", Lat: 39.277, Lon: -74.574},
+	}, {
+		desc:      "no match",
+		query:     "zzzz",
+		expectErr: ErrNotFound,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := testCatalog().Find(test.query)
+			if !errors.Is(err, test.expectErr) {
+				t.Fatalf("expected error '%v'; got '%v'", test.expectErr, err)
+			}
+
+			if got != test.expect {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
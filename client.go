@@ -2,17 +2,18 @@
 package seaweed
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/mdb/seaweed/internal/lru"
 	"github.com/sirupsen/logrus"
 )
 
+// DefaultMaxCachedSpots is the default maximum number of spots whose last
+// successfully fetched forecasts a Client caches.
+const DefaultMaxCachedSpots = 1000
+
 // Clock is a clock interface used to report the current time such that the
 // Client#Today and Client#Tomorrow methods can return the proper forecasts
 // relative to the current time.
@@ -43,6 +44,33 @@ type Client struct {
 	// Client#Tomorrow and Client#Today methods can return the proper forecasts
 	// relative to the current time.
 	clock Clock
+	// units is the unit system in which the API reports forecasts, such as "us",
+	// "uk", or "eu". The API's default is used if it is empty.
+	units string
+	// strict configures the Client to reject unknown fields and invalid
+	// forecasts; see WithStrictDecoding.
+	strict bool
+	// maxResponseBytes is the maximum number of response body bytes the Client
+	// will read from the Magic Seaweed API before failing with ErrResponseTooLarge.
+	maxResponseBytes int64
+	// maxStale is the maximum age of a cached forecast the Client will serve
+	// when the API fails; see WithStaleIfError.
+	maxStale time.Duration
+	// maxCachedSpots is the maximum number of spots cache holds; see
+	// WithMaxCachedSpots.
+	maxCachedSpots int
+	// mu guards cache.
+	mu sync.Mutex
+	// cache holds the last successful forecast response for each of the
+	// most recently fetched spot IDs.
+	cache *lru.Cache[string, *cachedForecast]
+	// archiver, if set, records freshly fetched forecasts; see WithArchive.
+	archiver Archiver
+	// provider fetches forecasts; it defaults to the Magic Seaweed API.
+	provider Provider
+	// locate, if set, resolves spots to coordinates such that Today, Tomorrow
+	// and Weekend return only daylight forecasts; see WithDaylight.
+	locate Locator
 }
 
 // ClientOption configures one or more Client fields.
@@ -69,126 +97,163 @@ func WithLogger(l *logrus.Logger) ClientOption {
 	}
 }
 
-// WithLogger is a ClientOption to configure a *Client's clock.
+// WithClock is a ClientOption to configure a *Client's clock.
 func WithClock(clock Clock) ClientOption {
 	return func(c *Client) {
 		c.clock = clock
 	}
 }
 
+// WithMaxResponseBytes is a ClientOption to configure the maximum number of
+// response body bytes a *Client reads from the Magic Seaweed API. It defaults
+// to DefaultMaxResponseBytes; a non-positive n is ignored.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseBytes = n
+		}
+	}
+}
+
+// WithUnits is a ClientOption to configure the unit system in which a *Client
+// requests forecasts, such as "us", "uk", or "eu".
+func WithUnits(units string) ClientOption {
+	return func(c *Client) {
+		c.units = units
+	}
+}
+
+// WithStrictDecoding is a ClientOption configuring a *Client to reject API
+// responses containing unknown fields, as well as forecasts failing validation
+// (see ValidateForecasts), rather than silently decoding them into zero values.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithStaleIfError is a ClientOption configuring a *Client to fall back to a
+// spot's last successfully fetched forecasts, so long as they were fetched no
+// more than maxStale ago, when fetching the spot's forecasts fails. See
+// StaleError.
+func WithStaleIfError(maxStale time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxStale = maxStale
+	}
+}
+
+// WithMaxCachedSpots is a ClientOption to configure the maximum number of
+// spots whose last successfully fetched forecasts a *Client caches, for
+// revalidation and for use WithStaleIfError and WithArchive, evicting the
+// least recently fetched spot beyond it. It defaults to DefaultMaxCachedSpots.
+func WithMaxCachedSpots(n int) ClientOption {
+	return func(c *Client) {
+		c.maxCachedSpots = n
+	}
+}
+
 // NewClient takes an API key and returns a seaweed API client.
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		"https://magicseaweed.com",
-		apiKey,
-		&http.Client{},
-		logrus.New(),
-		RealClock{},
+		baseURL:          "https://magicseaweed.com",
+		apiKey:           apiKey,
+		httpClient:       &http.Client{},
+		Logger:           logrus.New(),
+		clock:            RealClock{},
+		maxResponseBytes: DefaultMaxResponseBytes,
+		maxCachedSpots:   DefaultMaxCachedSpots,
 	}
+	c.provider = newMagicSeaweed(c)
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	c.cache = lru.New[string, *cachedForecast](c.maxCachedSpots)
+
 	return c
 }
 
 // Forecast fetches the full, multi-day forecast for a given spot ID.
 //
 // A spot's ID appears in its URL. For example, Ocean City, NJ's spot ID is 391:
-// https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/
+// https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/. ParseSpotURL extracts
+// the spot ID from such URLs.
 //
 // Note that the Magic Seaweed API may respond with an HTTP status code of 200
 // and a response body reporting an error (see APIError). Forecast attempts to
 // handle such instances by returning an error surfacing the response body error
 // message.
+//
+// If the Client is configured WithStaleIfError and the API call fails, Forecast
+// may return the spot's last successfully fetched forecasts alongside a
+// *StaleError wrapping the failure.
 func (c *Client) Forecast(spot string) ([]Forecast, error) {
 	forecasts, err := c.getForecast(spot)
 	if err != nil {
+		if stale, staleErr := c.stale(spot, err); staleErr != nil {
+			return stale, staleErr
+		}
+
 		return forecasts, err
 	}
 
 	return forecasts, nil
 }
 
-// Today fetches the today's forecast for a given spot ID.
+// Today fetches the today's forecast for a given spot ID. If the Client is
+// configured WithDaylight, only forecasts between first and last light are
+// returned.
 func (c *Client) Today(spot string) ([]Forecast, error) {
-	var today []Forecast
-	now := c.clock.Now().UTC()
-	forecasts, err := c.Forecast(spot)
-	if err != nil {
-		return today, err
-	}
-
-	for _, each := range forecasts {
-		if each.IsDay(now) {
-			today = append(today, each)
-		}
-	}
-
-	return today, nil
+	return c.Filtered(spot, append(c.daylight(spot), OnDay(c.clock.Now().UTC()))...)
 }
 
-// Tomorrow fetches tomorrow's forecast for a given spot ID.
+// Tomorrow fetches tomorrow's forecast for a given spot ID. If the Client is
+// configured WithDaylight, only forecasts between first and last light are
+// returned.
 func (c *Client) Tomorrow(spot string) ([]Forecast, error) {
-	var tomorrow []Forecast
-	tomorrowD := c.clock.Now().UTC().AddDate(0, 0, 1)
-	forecasts, err := c.Forecast(spot)
-	if err != nil {
-		return tomorrow, err
-	}
-
-	for _, each := range forecasts {
-		if each.IsDay(tomorrowD) {
-			tomorrow = append(tomorrow, each)
-		}
-	}
-
-	return tomorrow, nil
+	return c.Filtered(spot, append(c.daylight(spot), OnDay(c.clock.Now().UTC().AddDate(0, 0, 1)))...)
 }
 
-// Weekend fetches the weekend's forecast for a given spot ID.
+// Weekend fetches the weekend's forecast for a given spot ID. If the Client is
+// configured WithDaylight, only forecasts between first and last light are
+// returned.
 func (c *Client) Weekend(spot string) ([]Forecast, error) {
-	var weekendFs []Forecast
-	forecasts, err := c.Forecast(spot)
-	if err != nil {
-		return weekendFs, err
-	}
+	return c.Filtered(spot, append(c.daylight(spot), OnWeekend())...)
+}
 
-	for _, each := range forecasts {
-		if each.IsWeekend() {
-			weekendFs = append(weekendFs, each)
-		}
+// Filtered fetches the forecast for a given spot ID and returns the forecasts
+// satisfying all of the predicates it's passed; see Filter.
+func (c *Client) Filtered(spot string, preds ...Predicate) ([]Forecast, error) {
+	forecasts, err := c.Forecast(spot)
+	if err != nil && !IsStale(err) {
+		return nil, err
 	}
 
-	return weekendFs, nil
+	return Filter(forecasts, preds...), err
 }
 
 func (c *Client) getForecast(spotID string) ([]Forecast, error) {
-	url := fmt.Sprintf("%s/api/%s/forecast/?spot_id=%s", c.baseURL, c.apiKey, spotID)
-	forecasts := []Forecast{}
-	body, err := c.get(url)
+	forecasts, err := c.provider.Forecast(spotID)
 	if err != nil {
-		return forecasts, err
+		return []Forecast{}, err
 	}
 
-	switch {
-	case strings.Contains(string(body), "error_response"):
-		var errResp APIError
-		err = json.Unmarshal(body, &errResp)
-		if err != nil {
-			return forecasts, fmt.Errorf("unexpected API response '%s': %w", body, err)
+	if c.strict {
+		if err := ValidateForecasts(forecasts); err != nil {
+			return []Forecast{}, err
 		}
+	}
 
-		return forecasts, errors.New(errResp.ErrorResponse.ErrorMsg)
-	default:
-		err = json.Unmarshal(body, &forecasts)
-		if err != nil {
-			return forecasts, fmt.Errorf("unexpected API response '%s': %w", body, err)
+	if c.archiver != nil && c.isNewIssue(spotID, forecasts) {
+		if err := c.archiver.Archive(spotID, copyForecasts(forecasts)); err != nil {
+			c.Logger.WithError(err).WithField("spot", spotID).Warn("failed to archive forecast")
 		}
-
-		return forecasts, nil
 	}
+
+	c.store(spotID, forecasts)
+
+	return copyForecasts(forecasts), nil
 }
 
 // Get is a convenience function that fetches the []Forecast associated with the
@@ -198,34 +263,3 @@ func Get(key, location string) ([]Forecast, error) {
 
 	return c.Forecast(location)
 }
-
-func (c *Client) get(url string) ([]byte, error) {
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	sanitizedURL := strings.Replace(url, c.apiKey, "<REDACTED>", 1)
-	sanitizedURL = strings.Replace(sanitizedURL, c.baseURL, "", 1)
-
-	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("GET %s returned HTTP status code %d", sanitizedURL, resp.StatusCode)
-	}
-
-	l := c.Logger.WithFields(
-		logrus.Fields{
-			"url":         sanitizedURL,
-			"http_status": resp.StatusCode,
-			"body":        string(body),
-		})
-
-	l.Debugf("Magic Seaweed API response")
-
-	return body, err
-}
@@ -137,7 +137,7 @@ func TestForecast(t *testing.T) {
 			}
 
 			if test.expectError == nil && err == nil {
-				if forecasts[0].LocalTimestamp != test.expectLocalTimestamp {
+				if int64(forecasts[0].LocalTimestamp) != test.expectLocalTimestamp {
 					t.Errorf("expected LocalTimestamp '%d'; got '%d'", test.expectLocalTimestamp, forecasts[0].LocalTimestamp)
 				}
 			}
@@ -200,7 +200,7 @@ func TestWeekend(t *testing.T) {
 			}
 
 			if test.expectError == nil && err == nil {
-				if forecasts[0].LocalTimestamp != test.expectLocalTimestamp {
+				if int64(forecasts[0].LocalTimestamp) != test.expectLocalTimestamp {
 					t.Errorf("expected LocalTimestamp '%d'; got '%d'", test.expectLocalTimestamp, forecasts[0].LocalTimestamp)
 				}
 			}
@@ -263,7 +263,7 @@ func TestToday(t *testing.T) {
 			}
 
 			if test.expectError == nil && err == nil {
-				if forecasts[0].LocalTimestamp != test.expectLocalTimestamp {
+				if int64(forecasts[0].LocalTimestamp) != test.expectLocalTimestamp {
 					t.Errorf("expected LocalTimestamp '%d'; got '%d'", test.expectLocalTimestamp, forecasts[0].LocalTimestamp)
 				}
 			}
@@ -326,10 +326,34 @@ func TestTomorrow(t *testing.T) {
 			}
 
 			if test.expectError == nil && err == nil {
-				if forecasts[0].LocalTimestamp != test.expectLocalTimestamp {
+				if int64(forecasts[0].LocalTimestamp) != test.expectLocalTimestamp {
 					t.Errorf("expected LocalTimestamp '%d'; got '%d'", test.expectLocalTimestamp, forecasts[0].LocalTimestamp)
 				}
 			}
 		})
 	}
 }
+
+func TestWithUnits(t *testing.T) {
+	var units string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		units = r.URL.Query().Get("units")
+		fmt.Fprint(w, resp)
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		"fakeKey",
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithUnits("eu"),
+	)
+
+	if _, err := c.Forecast("123"); err != nil {
+		t.Fatalf("expected Forecast not to error; got '%v'", err)
+	}
+
+	if units != "eu" {
+		t.Errorf("expected units 'eu'; got '%s'", units)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mdb/seaweed"
+)
+
+const (
+	// defaultChartWidth is the width, in columns, of charts when neither the
+	// -width flag, the terminal, nor the COLUMNS environment variable
+	// configures it.
+	defaultChartWidth = 80
+	// maxCellWidth is the maximum width, in columns, of each timestep.
+	maxCellWidth = 3
+	// heightRows is the number of rows of the breaking height bar chart.
+	heightRows = 4
+	// daySeparator separates the timesteps of consecutive local days.
+	daySeparator = '│'
+)
+
+// sparks are the eighth blocks from which sparklines and the tops of bars are
+// drawn, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// arrows are the directions in which the wind may blow, clockwise from north.
+var arrows = []rune("↑↗→↘↓↙←↖")
+
+// chartWidth returns width, if positive, else the width of the terminal to
+// which w writes, if any, else the COLUMNS environment variable, if set, else
+// defaultChartWidth.
+func chartWidth(width int, w io.Writer) int {
+	if width > 0 {
+		return width
+	}
+
+	if f, ok := w.(*os.File); ok {
+		if n, ok := terminalWidth(f); ok {
+			return n
+		}
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+
+	return defaultChartWidth
+}
+
+// chartRow is a labelled row of a chart, drawing a rune for each forecast.
+type chartRow struct {
+	label string
+	cell  func(f seaweed.Forecast) rune
+}
+
+// writeChart draws the forecasts as charts over time, one column or more per
+// timestep, separating local days: a bar chart of the breaking height range,
+// sparklines of the primary swell period and wind speed, the direction in
+// which the wind blows, and the star rating. The charts fit within width
+// columns; timesteps that don't fit are omitted and noted.
+func writeChart(w io.Writer, forecasts []seaweed.Forecast, width int) error {
+	if len(forecasts) == 0 {
+		return nil
+	}
+
+	rows := chartRows(forecasts)
+
+	labelWidth := 0
+	for _, r := range rows {
+		if n := utf8.RuneCountInString(r.label); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	labelWidth++
+
+	visible, cellWidth := fitChart(forecasts, width-labelWidth)
+	shown := forecasts[:visible]
+
+	lines := []string{pad("", labelWidth) + dayHeader(shown, cellWidth)}
+	for _, r := range rows {
+		var b strings.Builder
+		b.WriteString(pad(r.label, labelWidth))
+
+		for i, f := range shown {
+			if i > 0 && newDay(shown[i-1], f) {
+				b.WriteRune(daySeparator)
+			}
+
+			b.WriteString(strings.Repeat(string(r.cell(f)), cellWidth))
+		}
+
+		lines = append(lines, b.String())
+	}
+
+	if hidden := len(forecasts) - visible; hidden > 0 {
+		lines = append(lines, fmt.Sprintf("… %d more timesteps; widen the terminal or pass -width", hidden))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chartRows returns the rows charting the forecasts, scaled to their ranges.
+func chartRows(forecasts []seaweed.Forecast) []chartRow {
+	var rows []chartRow
+
+	top := 0.0
+	minPeriod, maxPeriod := math.Inf(1), math.Inf(-1)
+	minWind, maxWind := math.Inf(1), math.Inf(-1)
+	peak := 1
+
+	for _, f := range forecasts {
+		top = math.Max(top, float64(f.Swell.AbsMaxBreakingHeight))
+		minPeriod = math.Min(minPeriod, float64(f.Swell.Components.Primary.Period))
+		maxPeriod = math.Max(maxPeriod, float64(f.Swell.Components.Primary.Period))
+		minWind = math.Min(minWind, float64(f.Wind.Speed))
+		maxWind = math.Max(maxWind, float64(f.Wind.Speed))
+
+		if n := seaweed.ClampRating(int(f.SolidRating) + int(f.FadedRating)); n > peak {
+			peak = n
+		}
+	}
+
+	top = math.Max(math.Ceil(top), 1)
+	unit := forecasts[0].Swell.Unit
+
+	for r := 0; r < heightRows; r++ {
+		lo := top * float64(heightRows-1-r) / heightRows
+		hi := top * float64(heightRows-r) / heightRows
+
+		var label string
+		switch r {
+		case 0:
+			label = fmt.Sprintf("height %g%s", top, unit)
+		case heightRows - 1:
+			label = "0"
+		}
+
+		rows = append(rows, chartRow{label, func(f seaweed.Forecast) rune {
+			return heightCell(float64(f.Swell.AbsMinBreakingHeight), float64(f.Swell.AbsMaxBreakingHeight), lo, hi)
+		}})
+	}
+
+	rows = append(rows, chartRow{
+		fmt.Sprintf("period %g-%gs", minPeriod, maxPeriod),
+		func(f seaweed.Forecast) rune {
+			return spark(float64(f.Swell.Components.Primary.Period), minPeriod, maxPeriod)
+		},
+	}, chartRow{
+		fmt.Sprintf("wind %g-%g%s", minWind, maxWind, forecasts[0].Wind.Unit),
+		func(f seaweed.Forecast) rune {
+			return spark(float64(f.Wind.Speed), minWind, maxWind)
+		},
+	}, chartRow{
+		"blowing",
+		func(f seaweed.Forecast) rune {
+			return arrow(f.Wind.Toward())
+		},
+	})
+
+	for r := 0; r < peak; r++ {
+		level := peak - r
+
+		var label string
+		if r == peak-1 {
+			label = "rating"
+		}
+
+		rows = append(rows, chartRow{label, func(f seaweed.Forecast) rune {
+			solid := seaweed.ClampRating(int(f.SolidRating))
+
+			switch {
+			case solid >= level:
+				return '★'
+			case seaweed.ClampRating(solid+int(f.FadedRating)) >= level:
+				return '☆'
+			default:
+				return ' '
+			}
+		}})
+	}
+
+	return rows
+}
+
+// fitChart returns the number of forecasts, and the width of each, that fit
+// within width columns alongside their day separators.
+func fitChart(forecasts []seaweed.Forecast, width int) (int, int) {
+	separators := 0
+	for i := 1; i < len(forecasts); i++ {
+		if newDay(forecasts[i-1], forecasts[i]) {
+			separators++
+		}
+	}
+
+	for cellWidth := maxCellWidth; cellWidth > 1; cellWidth-- {
+		if len(forecasts)*cellWidth+separators <= width {
+			return len(forecasts), cellWidth
+		}
+	}
+
+	used := 0
+	for i, f := range forecasts {
+		need := 1
+		if i > 0 && newDay(forecasts[i-1], f) {
+			need++
+		}
+
+		if used+need > width {
+			return i, 1
+		}
+		used += need
+	}
+
+	return len(forecasts), 1
+}
+
+// dayHeader labels each local day's timesteps, such as "Sat 4", truncating
+// labels to their day's width.
+func dayHeader(forecasts []seaweed.Forecast, cellWidth int) string {
+	var b strings.Builder
+
+	start := 0
+	for i := 1; i <= len(forecasts); i++ {
+		if i < len(forecasts) && !newDay(forecasts[i-1], forecasts[i]) {
+			continue
+		}
+
+		if start > 0 {
+			b.WriteRune(daySeparator)
+		}
+
+		label := localTime(forecasts[start]).Format("Mon 2")
+		b.WriteString(truncate(pad(label, (i-start)*cellWidth), (i-start)*cellWidth))
+		start = i
+	}
+
+	return b.String()
+}
+
+// heightCell returns the rune depicting the portion of the breaking height
+// range from min to max within the row spanning lo to hi: solid within the
+// range and shaded below it.
+func heightCell(min, max, lo, hi float64) rune {
+	switch {
+	case max <= lo:
+		return ' '
+	case min >= hi:
+		return '░'
+	case max >= hi:
+		return '█'
+	}
+
+	i := int(math.Round((max-lo)/(hi-lo)*float64(len(sparks)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sparks[i]
+}
+
+// spark returns the sparkline rune depicting v within the range min to max.
+func spark(v, min, max float64) rune {
+	if max <= min {
+		return sparks[len(sparks)/2-1]
+	}
+
+	return sparks[int(math.Round((v-min)/(max-min)*float64(len(sparks)-1)))]
+}
+
+// arrow returns the arrow pointing in the direction d.
+func arrow(d seaweed.Direction) rune {
+	step := 360 / float64(len(arrows))
+
+	return arrows[int(math.Round(float64(d.Normalize())/step))%len(arrows)]
+}
+
+func localTime(f seaweed.Forecast) time.Time {
+	return time.Unix(int64(f.LocalTimestamp), 0).UTC()
+}
+
+// newDay returns true if b falls on a different local day than a.
+func newDay(a, b seaweed.Forecast) bool {
+	return localTime(a).Format("2006-01-02") != localTime(b).Format("2006-01-02")
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
+
+func truncate(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+
+	return s
+}
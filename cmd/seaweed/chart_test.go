@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+// chartFixture returns forecasts spanning two local days.
+func chartFixture() []seaweed.Forecast {
+	step := func(local int64, min, max seaweed.FlexFloat, period, wind int, toward int64, solid, faded int) seaweed.Forecast {
+		f := seaweed.Forecast{
+			Timestamp:      seaweed.FlexInt64(local + 5*3600),
+			LocalTimestamp: seaweed.FlexInt64(local),
+			SolidRating:    seaweed.FlexInt(solid),
+			FadedRating:    seaweed.FlexInt(faded),
+		}
+		f.Swell.AbsMinBreakingHeight = min
+		f.Swell.AbsMaxBreakingHeight = max
+		f.Swell.Unit = "ft"
+		f.Swell.Components.Primary.Period = seaweed.FlexInt(period)
+		f.Wind = seaweed.Wind{Speed: seaweed.FlexInt(wind), Direction: seaweed.FlexInt64(toward), Unit: "mph"}
+
+		return f
+	}
+
+	return []seaweed.Forecast{
+		step(1677913200, 1, 2, 8, 5, 0, 0, 1),         // Sat 2023-03-04 07:00
+		step(1677924000, 2, 4, 10, 10, 90, 1, 1),      // Sat 2023-03-04 10:00
+		step(1677999600, 3.5, 3.9, 12, 15, 225, 2, 0), // Sun 2023-03-05 07:00
+	}
+}
+
+func TestWriteChart(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeChart(&buf, chartFixture(), 80); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := strings.Join([]string{
+		"             Sat 4 │Sun",
+		"height 4ft      ███│▇▇▇",
+		"                ███│░░░",
+		"             ███░░░│░░░",
+		"0            ░░░░░░│░░░",
+		"period 8-12s ▁▁▁▅▅▅│███",
+		"wind 5-15mph ▁▁▁▅▅▅│███",
+		"blowing      ↑↑↑→→→│↙↙↙",
+		"                ☆☆☆│★★★",
+		"rating       ☆☆☆★★★│★★★",
+		"",
+	}, "\n")
+
+	if got := buf.String(); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestWriteChart_narrow(t *testing.T) {
+	tests := []struct {
+		desc        string
+		width       int
+		expectLines []string
+	}{{
+		desc:  "single-column timesteps",
+		width: 17,
+		expectLines: []string{
+			"             Sa│S",
+			"period 8-12s ▁▅│█",
+		},
+	}, {
+		desc:  "omitted timesteps",
+		width: 15,
+		expectLines: []string{
+			"             Sa",
+			"period 8-12s ▁▅",
+			"… 1 more timesteps; widen the terminal or pass -width",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeChart(&buf, chartFixture(), test.width); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(buf.String(), "\n")
+			for _, expect := range test.expectLines {
+				found := false
+				for _, line := range lines {
+					found = found || line == expect
+				}
+
+				if !found {
+					t.Errorf("expected line '%s'; got:\n%s", expect, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteChart_empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeChart(&buf, nil, 80); err != nil || buf.Len() != 0 {
+		t.Errorf("expected no chart; got '%s' and '%v'", buf.String(), err)
+	}
+}
+
+func TestChartWidth(t *testing.T) {
+	tests := []struct {
+		desc    string
+		width   int
+		columns string
+		expect  int
+	}{{
+		desc:    "the -width flag",
+		width:   120,
+		columns: "100",
+		expect:  120,
+	}, {
+		desc:    "COLUMNS",
+		columns: "100",
+		expect:  100,
+	}, {
+		desc:    "an invalid COLUMNS",
+		columns: "wide",
+		expect:  defaultChartWidth,
+	}, {
+		desc:   "the default",
+		expect: defaultChartWidth,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("COLUMNS", test.columns)
+
+			if got := chartWidth(test.width, &bytes.Buffer{}); got != test.expect {
+				t.Errorf("expected '%d'; got '%d'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestTerminalWidth_notATerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "chart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if n, ok := terminalWidth(f); ok {
+		t.Errorf("expected a regular file not to be a terminal; got '%d' columns", n)
+	}
+
+	t.Setenv("COLUMNS", "100")
+
+	if got := chartWidth(0, f); got != 100 {
+		t.Errorf("expected '100'; got '%d'", got)
+	}
+}
+
+func TestArrow(t *testing.T) {
+	tests := []struct {
+		direction seaweed.Direction
+		expect    rune
+	}{
+		{0, '↑'},
+		{22, '↑'},
+		{23, '↗'},
+		{90, '→'},
+		{180, '↓'},
+		{-90, '←'},
+		{337, '↖'},
+		{350, '↑'},
+	}
+
+	for _, test := range tests {
+		if got := arrow(test.direction); got != test.expect {
+			t.Errorf("expected '%g' to be '%c'; got '%c'", test.direction, test.expect, got)
+		}
+	}
+}
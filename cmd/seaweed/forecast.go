@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/catalog"
+	"github.com/mdb/seaweed/notify"
+)
+
+// newClient returns the *seaweed.Client with which forecast commands fetch
+// forecasts. It exists largely for testing purposes.
+var newClient = seaweed.NewClient
+
+// forecastCommand returns the run function of a command printing a spot's
+// forecasts, as fetched by fetch.
+func forecastCommand(name string, fetch func(c *seaweed.Client, spot string) ([]seaweed.Forecast, error)) func(args []string, stdout, stderr io.Writer) error {
+	return func(args []string, stdout, stderr io.Writer) error {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "Usage: seaweed %s [flags] <spot name, ID, or URL>\n", name)
+			fs.PrintDefaults()
+		}
+		units := fs.String("units", "", "unit system: us, uk, or eu")
+		catalogPath := fs.String("catalog", "", "JSON spot catalog file merged over the built-in catalog")
+		asJSON := fs.Bool("json", false, "print forecasts as JSON")
+		chart := fs.Bool("chart", false, "draw forecasts as charts over time")
+		width := fs.Int("width", 0, "chart width in columns; defaults to the terminal width, else $COLUMNS, else 80")
+		daylight := fs.Bool("daylight", false, "print only forecasts between first and last light; requires a spot with catalog coordinates")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		if fs.NArg() == 0 {
+			fs.Usage()
+			return errors.New("a spot is required")
+		}
+
+		cat := catalog.Default()
+		if *catalogPath != "" {
+			extra, err := catalog.ReadFile(*catalogPath)
+			if err != nil {
+				return err
+			}
+
+			cat.Merge(extra.Spots()...)
+		}
+
+		spot, err := cat.Find(strings.Join(fs.Args(), " "))
+		if err != nil {
+			return err
+		}
+
+		if *chart && *asJSON {
+			return errors.New("-chart and -json are mutually exclusive")
+		}
+
+		if *daylight && spot.Lat == 0 && spot.Lon == 0 {
+			return fmt.Errorf("-daylight requires coordinates; spot %s has none in the catalog", spot.ID)
+		}
+
+		key, err := apiKey()
+		if err != nil {
+			return err
+		}
+
+		var opts []seaweed.ClientOption
+		if *units != "" {
+			opts = append(opts, seaweed.WithUnits(*units))
+		}
+
+		forecasts, err := fetch(newClient(key, opts...), string(spot.ID))
+		if err != nil && !seaweed.IsStale(err) {
+			return err
+		}
+
+		if *daylight {
+			forecasts = seaweed.Filter(forecasts, seaweed.InDaylight(spot.Lat, spot.Lon))
+		}
+
+		if *asJSON {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+
+			return enc.Encode(forecasts)
+		}
+
+		if err != nil {
+			fmt.Fprintf(stderr, "warning: %s\n", err)
+		}
+
+		title := string(spot.ID)
+		if spot.Name != "" {
+			title = fmt.Sprintf("%s (%s)", spot.Name, spot.ID)
+		}
+
+		fmt.Fprintln(stdout, title)
+		if len(forecasts) == 0 {
+			fmt.Fprintln(stdout, "no forecasts")
+			return nil
+		}
+
+		if *chart {
+			return writeChart(stdout, forecasts, chartWidth(*width, stdout))
+		}
+
+		msg := notify.Message{Forecasts: forecasts}
+		for _, line := range msg.Lines() {
+			fmt.Fprintln(stdout, line)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+type testClock struct{}
+
+func (testClock) Now() time.Time {
+	return time.Unix(1442355356, 0)
+}
+
+// testForecastServer serves the forecast fixture, recording the requested spot
+// IDs, and configures newClient to target it.
+func testForecastServer(t *testing.T) *[]string {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("..", "..", "testdata", "response.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spots []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spots = append(spots, r.URL.Query().Get("spot_id"))
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	newClient = func(key string, opts ...seaweed.ClientOption) *seaweed.Client {
+		return seaweed.NewClient(key, append(opts,
+			seaweed.WithBaseURL(server.URL),
+			seaweed.WithHTTPClient(server.Client()),
+			seaweed.WithClock(testClock{}),
+		)...)
+	}
+	t.Cleanup(func() { newClient = seaweed.NewClient })
+
+	return &spots
+}
+
+func TestForecastCommands(t *testing.T) {
+	catalogPath := filepath.Join(t.TempDir(), "spots.json")
+	if err := os.WriteFile(catalogPath, []byte(`[{"id":"999","name":"Secret Spot"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc         string
+		args         []string
+		expectCode   int
+		expectSpot   string
+		expectStdout []string
+		rejectStdout string
+		expectStderr string
+	}{{
+		desc:         "today by name",
+		args:         []string{"today", "ocean city"},
+		expectSpot:   "391",
+		expectStdout: []string{"Ocean City, NJ (391)", "Tue Sep 15 22:15 · ☆☆☆ · 5-8ft"},
+	}, {
+		desc:         "forecast by unquoted name",
+		args:         []string{"forecast", "ocean", "city"},
+		expectSpot:   "391",
+		expectStdout: []string{"Ocean City, NJ (391)", "Sat Mar 4"},
+	}, {
+		desc:         "tomorrow by URL",
+		args:         []string{"tomorrow", "https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/"},
+		expectSpot:   "391",
+		expectStdout: []string{"Ocean City, NJ (391)", "Wed Sep 16"},
+	}, {
+		desc:         "weekend by uncataloged ID",
+		args:         []string{"weekend", "1234"},
+		expectSpot:   "1234",
+		expectStdout: []string{"1234\n"},
+	}, {
+		desc:         "JSON",
+		args:         []string{"today", "-json", "391"},
+		expectSpot:   "391",
+		expectStdout: []string{`"timestamp": 1442355356`},
+	}, {
+		desc:         "catalog file",
+		args:         []string{"today", "-catalog", catalogPath, "secret spot"},
+		expectSpot:   "999",
+		expectStdout: []string{"Secret Spot (999)"},
+	}, {
+		// The March forecast falls after last light in Ocean City.
+		desc:         "daylight",
+		args:         []string{"forecast", "-daylight", "391"},
+		expectSpot:   "391",
+		expectStdout: []string{"Tue Sep 15 22:15", "Wed Sep 16 22:15"},
+		rejectStdout: "Sat Mar 4",
+	}, {
+		desc:         "daylight without coordinates",
+		args:         []string{"today", "-daylight", "1234"},
+		expectCode:   1,
+		expectStderr: "seaweed today: -daylight requires coordinates; spot 1234 has none in the catalog",
+	}, {
+		desc:         "chart",
+		args:         []string{"forecast", "-chart", "-width", "60", "391"},
+		expectSpot:   "391",
+		expectStdout: []string{"Ocean City, NJ (391)", "Tue│Wed│Sat", "blowing"},
+		rejectStdout: "Tue Sep 15 22:15",
+	}, {
+		desc:         "chart and JSON",
+		args:         []string{"today", "-chart", "-json", "391"},
+		expectCode:   1,
+		expectStderr: "seaweed today: -chart and -json are mutually exclusive",
+	}, {
+		desc:         "unknown spot",
+		args:         []string{"today", "zzzz"},
+		expectCode:   1,
+		expectStderr: `seaweed today: no matching spot: "zzzz"`,
+	}, {
+		desc:         "no spot",
+		args:         []string{"today"},
+		expectCode:   1,
+		expectStderr: "seaweed today: a spot is required",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv(apiKeyEnvVar, "fakeKey")
+			spots := testForecastServer(t)
+
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, &stdout, &stderr)
+
+			if code != test.expectCode {
+				t.Fatalf("expected exit code '%d'; got '%d': %s", test.expectCode, code, stderr.String())
+			}
+
+			if test.expectSpot != "" && (len(*spots) != 1 || (*spots)[0] != test.expectSpot) {
+				t.Errorf("expected spot '%s' to be requested; got '%v'", test.expectSpot, *spots)
+			}
+
+			for _, expect := range test.expectStdout {
+				if !strings.Contains(stdout.String(), expect) {
+					t.Errorf("expected stdout to contain '%s'; got '%s'", expect, stdout.String())
+				}
+			}
+
+			if test.rejectStdout != "" && strings.Contains(stdout.String(), test.rejectStdout) {
+				t.Errorf("expected stdout not to contain '%s'; got '%s'", test.rejectStdout, stdout.String())
+			}
+
+			if !strings.Contains(stderr.String(), test.expectStderr) {
+				t.Errorf("expected stderr to contain '%s'; got '%s'", test.expectStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Command seaweed is a Magic Seaweed API command line interface.
+//
+// Usage:
+//
+//	seaweed <command> [flags]
+//
+// The commands are:
+//
+//	forecast print a spot's full forecast
+//	today    print a spot's forecast for today
+//	tomorrow print a spot's forecast for tomorrow
+//	weekend  print a spot's forecast for the weekend
+//	serve    serve a JSON REST API over the Magic Seaweed API
+//	verify   report the skill of archived forecasts by lead time
+//
+// Spots may be referenced by name, such as seaweed today "ocean city", by ID,
+// or by Magic Seaweed surf report URL.
+//
+// The Magic Seaweed API key is read from the MAGIC_SEAWEED_API_KEY environment
+// variable.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mdb/seaweed"
+)
+
+// apiKeyEnvVar is the environment variable from which the Magic Seaweed API
+// key is read.
+const apiKeyEnvVar = "MAGIC_SEAWEED_API_KEY"
+
+// command is a seaweed subcommand.
+type command struct {
+	name string
+	desc string
+	run  func(args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"forecast", "print a spot's full forecast", forecastCommand("forecast", (*seaweed.Client).Forecast)},
+	{"today", "print a spot's forecast for today", forecastCommand("today", (*seaweed.Client).Today)},
+	{"tomorrow", "print a spot's forecast for tomorrow", forecastCommand("tomorrow", (*seaweed.Client).Tomorrow)},
+	{"weekend", "print a spot's forecast for the weekend", forecastCommand("weekend", (*seaweed.Client).Weekend)},
+	{"serve", "serve a JSON REST API over the Magic Seaweed API", runServe},
+	{"verify", "report the skill of archived forecasts by lead time", runVerify},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:], stdout, stderr); err != nil {
+				fmt.Fprintf(stderr, "seaweed %s: %s\n", cmd.name, err)
+				return 1
+			}
+
+			return 0
+		}
+	}
+
+	fmt.Fprintf(stderr, "seaweed: unknown command %q\n", args[0])
+	usage(stderr)
+
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: seaweed <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.desc)
+	}
+}
+
+// apiKey returns the Magic Seaweed API key from the environment.
+func apiKey() (string, error) {
+	key := os.Getenv(apiKeyEnvVar)
+	if key == "" {
+		return "", fmt.Errorf("%s environment variable not set", apiKeyEnvVar)
+	}
+
+	return key, nil
+}
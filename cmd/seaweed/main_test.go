@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		desc         string
+		args         []string
+		env          string
+		expectCode   int
+		expectStderr string
+	}{{
+		desc:         "when no command is passed",
+		args:         []string{},
+		expectCode:   2,
+		expectStderr: "Usage: seaweed <command> [flags]",
+	}, {
+		desc:         "when an unknown command is passed",
+		args:         []string{"foo"},
+		expectCode:   2,
+		expectStderr: `seaweed: unknown command "foo"`,
+	}, {
+		desc:         "when serve is run without an API key",
+		args:         []string{"serve"},
+		expectCode:   1,
+		expectStderr: "seaweed serve: MAGIC_SEAWEED_API_KEY environment variable not set",
+	}, {
+		desc:         "when verify is run without an archive",
+		args:         []string{"verify"},
+		expectCode:   1,
+		expectStderr: "seaweed verify: -archive is required",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv(apiKeyEnvVar, test.env)
+
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, &stdout, &stderr)
+
+			if code != test.expectCode {
+				t.Errorf("expected exit code '%d'; got '%d'", test.expectCode, code)
+			}
+
+			if !strings.Contains(stderr.String(), test.expectStderr) {
+				t.Errorf("expected stderr to contain '%s'; got '%s'", test.expectStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/archive"
+	"github.com/mdb/seaweed/server"
+	"github.com/sirupsen/logrus"
+)
+
+func runServe(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "address on which to listen")
+	ttl := fs.Duration("cache-ttl", server.DefaultCacheTTL, "duration for which to cache each spot's forecasts")
+	maxStale := fs.Duration("max-stale", 6*time.Hour, "maximum age of cached forecasts served when the Magic Seaweed API fails")
+	archiveDir := fs.String("archive", "", "directory in which to archive fetched forecasts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, err := apiKey()
+	if err != nil {
+		return err
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(stderr)
+
+	clientOpts := []seaweed.ClientOption{seaweed.WithStaleIfError(*maxStale)}
+	if *archiveDir != "" {
+		a, err := archive.Open(*archiveDir)
+		if err != nil {
+			return err
+		}
+
+		clientOpts = append(clientOpts, seaweed.WithArchive(a))
+	}
+
+	s := server.New(
+		key,
+		server.WithCacheTTL(*ttl),
+		server.WithLogger(logger),
+		server.WithClientOptions(clientOpts...),
+	)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	logger.Infof("listening on %s", *addr)
+
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package main
+
+import "os"
+
+// terminalWidth returns false; querying the terminal size is unsupported on
+// this platform.
+func terminalWidth(f *os.File) (int, bool) {
+	return 0, false
+}
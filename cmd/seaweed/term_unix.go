@@ -0,0 +1,25 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the terminal size reported by the TIOCGWINSZ ioctl.
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// terminalWidth returns the width, in columns, of the terminal f refers to,
+// and false if f is not a terminal.
+func terminalWidth(f *os.File) (int, bool) {
+	var ws winsize
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws))); errno != 0 || ws.cols == 0 {
+		return 0, false
+	}
+
+	return int(ws.cols), true
+}
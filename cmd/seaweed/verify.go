@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/archive"
+	"github.com/mdb/seaweed/verify"
+)
+
+func runVerify(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("archive", "", "forecast archive directory, such as populated by serve -archive")
+	spot := fs.String("spot", "", "spot ID to verify; defaults to every archived spot")
+	bucket := fs.Duration("bucket", verify.DefaultLeadBucket, "width of lead time buckets")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		return errors.New("-archive is required")
+	}
+
+	a, err := archive.Open(*dir)
+	if err != nil {
+		return err
+	}
+
+	spots := a.Spots()
+	if *spot != "" {
+		spots = []string{*spot}
+	}
+
+	reports := map[string]verify.Report{}
+	for _, s := range spots {
+		archived, err := a.Issued(s, time.Time{}, time.Time{})
+		if err != nil {
+			return err
+		}
+
+		issues := make([][]seaweed.Forecast, len(archived))
+		for i, issue := range archived {
+			issues[i] = issue.Forecasts
+		}
+
+		reports[s] = verify.Verify(issues, verify.Latest(issues...), verify.WithLeadBucket(*bucket))
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(reports)
+	}
+
+	for i, s := range spots {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		fmt.Fprintf(stdout, "spot %s\n", s)
+		if len(reports[s]) == 0 {
+			fmt.Fprintln(stdout, "no verifiable forecasts")
+			continue
+		}
+
+		fmt.Fprint(stdout, reports[s])
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/archive"
+)
+
+func TestRunVerify(t *testing.T) {
+	dir := t.TempDir()
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC).Unix()
+	for i, height := range []seaweed.FlexFloat{5, 3} {
+		f := seaweed.Forecast{Timestamp: seaweed.FlexInt64(valid), IssueTimestamp: seaweed.FlexInt64(valid - int64(1-i)*24*60*60)}
+		f.Swell.Components.Combined.Height = height
+
+		if err := a.Archive("391", []seaweed.Forecast{f}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		desc   string
+		args   []string
+		expect []string
+	}{{
+		desc:   "table",
+		args:   []string{"verify", "-archive", dir},
+		expect: []string{"spot 391", "1d           1  +2.00/2.00/2.00"},
+	}, {
+		desc:   "JSON",
+		args:   []string{"verify", "-archive", dir, "-spot", "391", "-json"},
+		expect: []string{`"391": [`, `"bias": 2`},
+	}, {
+		desc:   "unarchived spot",
+		args:   []string{"verify", "-archive", dir, "-spot", "392"},
+		expect: []string{"spot 392", "no verifiable forecasts"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(test.args, &stdout, &stderr); code != 0 {
+				t.Fatalf("expected exit code '0'; got '%d': %s", code, stderr.String())
+			}
+
+			for _, expect := range test.expect {
+				if !strings.Contains(stdout.String(), expect) {
+					t.Errorf("expected stdout to contain '%s'; got '%s'", expect, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package seaweed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mdb/seaweed/solar"
+)
+
+// Locator resolves a spot, as passed to Client.Forecast, to a coordinate.
+type Locator func(spot string) (lat, lon float64, err error)
+
+// WithDaylight is a ClientOption configuring a *Client's Today, Tomorrow and
+// Weekend methods to return only forecasts between first and last light (see
+// InDaylight), locating spots using locate, such as by looking spot IDs up in
+// a spot catalog. Forecasts for spots locate fails to resolve are not filtered
+// by daylight.
+func WithDaylight(locate Locator) ClientOption {
+	return func(c *Client) {
+		c.locate = locate
+	}
+}
+
+// InDaylight returns a Predicate satisfied by forecasts whose Timestamp falls
+// between first and last light, the beginning of morning and end of evening
+// civil twilight, on their local day at the latitude and longitude, in
+// degrees north and east. Unlike Daylight, it tracks the seasons. The
+// Predicate is safe for concurrent use.
+func InDaylight(lat, lon float64) Predicate {
+	var mu sync.Mutex
+	days := map[time.Time]solar.Day{}
+
+	return func(f Forecast) bool {
+		date := localDate(f)
+
+		mu.Lock()
+		day, ok := days[date]
+		if !ok {
+			day = solar.Compute(date, lat, lon)
+			days[date] = day
+		}
+		mu.Unlock()
+
+		return day.Light(time.Unix(int64(f.Timestamp), 0))
+	}
+}
+
+// localDate returns the forecast's local day, per its LocalTimestamp,
+// expressed as midnight UTC.
+func localDate(f Forecast) time.Time {
+	t := time.Unix(int64(f.LocalTimestamp), 0).UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daylight returns the predicates restricting the spot's forecasts to daylight
+// if the Client is configured WithDaylight.
+func (c *Client) daylight(spot string) []Predicate {
+	if c.locate == nil {
+		return nil
+	}
+
+	lat, lon, err := c.locate(spot)
+	if err != nil {
+		c.Logger.WithError(err).WithField("spot", spot).Debug("not filtering forecasts by daylight")
+
+		return nil
+	}
+
+	return []Predicate{InDaylight(lat, lon)}
+}
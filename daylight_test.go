@@ -0,0 +1,119 @@
+package seaweed
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestInDaylight(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lat, lon float64
+		expect   []int64
+	}{{
+		// First light is near 11:00 UTC and last light near 23:15 UTC.
+		desc:   "at Ocean City, NJ",
+		lat:    39.277,
+		lon:    -74.574,
+		expect: []int64{1677931200, 1677942000},
+	}, {
+		// The Sunday forecast's local day is 5 March, on which first light in
+		// Tokyo is near 21:05 UTC on 4 March and last light near 09:10 UTC.
+		desc:   "at Tokyo",
+		lat:    35.68,
+		lon:    139.69,
+		expect: []int64{1678003200},
+	}, {
+		// The sun remains up all day at the South Pole until the equinox.
+		desc:   "during the midnight sun",
+		lat:    -89.9,
+		lon:    0,
+		expect: []int64{1677931200, 1677942000, 1678003200},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := Filter(filterFixture(), InDaylight(test.lat, test.lon))
+
+			if len(got) != len(test.expect) {
+				t.Fatalf("expected '%d' forecasts; got '%d'", len(test.expect), len(got))
+			}
+
+			for i, f := range got {
+				if int64(f.Timestamp) != test.expect[i] {
+					t.Errorf("expected forecast '%d' at '%d'; got '%d'", i, test.expect[i], f.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestInDaylight_concurrent(t *testing.T) {
+	inDaylight := InDaylight(39.277, -74.574)
+	forecasts := filterFixture()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if got := Filter(forecasts, inDaylight); len(got) != 2 {
+				t.Errorf("expected '2' forecasts; got '%d'", len(got))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithDaylight(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, resp)
+	}))
+	defer server.Close()
+
+	// Today's forecast is at 22:15 UTC, after last light at Greenwich but
+	// before last light at Ocean City, NJ.
+	tests := []struct {
+		desc   string
+		locate Locator
+		expect int
+	}{{
+		desc:   "when the spot is in daylight",
+		locate: func(string) (float64, float64, error) { return 39.277, -74.574, nil },
+		expect: 1,
+	}, {
+		desc:   "when the spot is in darkness",
+		locate: func(string) (float64, float64, error) { return 51.477, 0, nil },
+		expect: 0,
+	}, {
+		desc:   "when the spot cannot be located",
+		locate: func(string) (float64, float64, error) { return 0, 0, errors.New("unknown spot") },
+		expect: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			c := NewClient(
+				"fakeKey",
+				WithBaseURL(server.URL),
+				WithHTTPClient(server.Client()),
+				WithClock(testClock{}),
+				WithDaylight(test.locate),
+			)
+
+			forecasts, err := c.Today("123")
+			if err != nil {
+				t.Fatalf("expected no error; got '%v'", err)
+			}
+
+			if len(forecasts) != test.expect {
+				t.Errorf("expected '%d' forecasts; got '%d'", test.expect, len(forecasts))
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+const (
+	// DefaultMaxResponseBytes is the default maximum number of response body
+	// bytes a Client reads from the Magic Seaweed API.
+	DefaultMaxResponseBytes int64 = 10 << 20
+
+	// maxExcerptBytes is the maximum number of response body bytes surfaced in
+	// error messages.
+	maxExcerptBytes int64 = 512
+)
+
+// ErrResponseTooLarge is returned when a Magic Seaweed API response body
+// exceeds the Client's maximum response size, or a body read through
+// LimitReader exceeds its limit.
+var ErrResponseTooLarge = errors.New("response body exceeds maximum size")
+
+// LimitReader returns a reader reading from r until n bytes have been read,
+// at which point it fails with ErrResponseTooLarge if r has more to read. It
+// is used by Providers to bound response bodies as the Client does.
+func LimitReader(r io.Reader, n int64) io.Reader {
+	return &limitedReader{r: r, n: n}
+}
+
+// limitedReader reads from r until n bytes remain, at which point it fails
+// with ErrResponseTooLarge rather than io.EOF, such that a truncated body is
+// never mistaken for a complete one.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Distinguish a body of exactly n bytes from an oversized one.
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+
+	return n, err
+}
+
+// decodeForecasts streams a Magic Seaweed API forecast response from r.
+//
+// The API responds with a JSON array of forecasts on success and with a JSON
+// object on error (see APIError), regardless of HTTP status code. The first
+// token is inspected to determine which of the two r holds; forecasts are then
+// decoded one at a time rather than buffering the full body.
+//
+// If strict is true, forecasts containing fields unknown to Forecast are
+// rejected.
+func decodeForecasts(r io.Reader, strict bool) ([]Forecast, error) {
+	pr := &peekedReader{r: r}
+	first, err := pr.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(pr)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	if first == '{' {
+		apiErr := &APIError{}
+		if err := dec.Decode(apiErr); err != nil {
+			return nil, err
+		}
+
+		if apiErr.ErrorResponse.ErrorMsg == "" {
+			return nil, errors.New("response object is not an error response")
+		}
+
+		return nil, apiErr
+	}
+
+	if first != '[' {
+		// Let the decoder report the syntax error.
+		var v interface{}
+		return nil, dec.Decode(&v)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	forecasts := []Forecast{}
+	for dec.More() {
+		// Decode in place, rather than into a variable escaping to the heap.
+		forecasts = append(forecasts, Forecast{})
+		if err := dec.Decode(&forecasts[len(forecasts)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return forecasts, nil
+}
+
+// peekedReader reads from r, replaying the first non-whitespace byte read by
+// peek. Unlike a bufio.Reader, it allocates no buffer.
+type peekedReader struct {
+	r io.Reader
+	// first is the byte read by peek, pending if peeked is true.
+	first  [1]byte
+	peeked bool
+}
+
+// peek reads and returns the first non-whitespace byte of r, such that it is
+// the first byte subsequently read from pr.
+func (pr *peekedReader) peek() (byte, error) {
+	for {
+		n, err := pr.r.Read(pr.first[:])
+		if n == 1 {
+			switch pr.first[0] {
+			case ' ', '\t', '\r', '\n':
+				continue
+			}
+
+			pr.peeked = true
+
+			return pr.first[0], nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (pr *peekedReader) Read(p []byte) (int, error) {
+	if pr.peeked && len(p) > 0 {
+		pr.peeked = false
+		p[0] = pr.first[0]
+		n, err := pr.r.Read(p[1:])
+
+		return n + 1, err
+	}
+
+	return pr.r.Read(p)
+}
@@ -0,0 +1,259 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestForecast_maxResponseBytes(t *testing.T) {
+	tests := []struct {
+		desc        string
+		max         int64
+		expectError bool
+	}{{
+		desc: "when the response body is smaller than the limit",
+		max:  int64(len(resp)) + 1,
+	}, {
+		desc: "when the response body is exactly the limit",
+		max:  int64(len(resp)),
+	}, {
+		desc:        "when the response body exceeds the limit",
+		max:         int64(len(resp)) / 2,
+		expectError: true,
+	}, {
+		desc: "when the limit is zero",
+		max:  0,
+	}, {
+		desc: "when the limit is negative",
+		max:  -1,
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server, c := testServerAndClient(200, resp)
+			defer server.Close()
+			WithMaxResponseBytes(test.max)(c)
+
+			forecasts, err := c.Forecast("123")
+
+			if test.expectError && !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("expected error wrapping '%v'; got '%v'", ErrResponseTooLarge, err)
+			}
+
+			if !test.expectError && err != nil {
+				t.Errorf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if !test.expectError && len(forecasts) != 3 {
+				t.Errorf("expected '3' forecasts; got '%d'", len(forecasts))
+			}
+		})
+	}
+}
+
+func TestExcerptWriter(t *testing.T) {
+	tests := []struct {
+		desc   string
+		max    int64
+		writes []string
+		expect string
+	}{{
+		desc:   "when the writes fit",
+		max:    8,
+		writes: []string{"abc", "def"},
+		expect: "abcdef",
+	}, {
+		desc:   "when the writes exceed the maximum",
+		max:    4,
+		writes: []string{"abc", "def"},
+		expect: "abcd...",
+	}, {
+		desc:   "when the maximum is negative",
+		max:    -1,
+		writes: []string{"abc"},
+		expect: "...",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			e := &excerptWriter{max: test.max}
+			for _, w := range test.writes {
+				if n, err := e.Write([]byte(w)); n != len(w) || err != nil {
+					t.Errorf("expected '%d' bytes written; got '%d', '%v'", len(w), n, err)
+				}
+			}
+
+			if got := e.String(); got != test.expect {
+				t.Errorf("expected '%s'; got '%s'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestDecodeForecasts(t *testing.T) {
+	tests := []struct {
+		desc                string
+		body                string
+		expectError         error
+		expectForecastCount int
+	}{{
+		desc:                "when the body is an array of forecasts",
+		body:                resp,
+		expectForecastCount: 3,
+	}, {
+		desc:                "when the body is an empty array",
+		body:                " \n[]",
+		expectForecastCount: 0,
+	}, {
+		desc:        "when the body is an error response",
+		body:        errorResp,
+		expectError: errors.New("Unable to authenticate request: Ensure your API key is passed correctly. Refer to the API docs."),
+	}, {
+		desc:        "when the body is an object that is not an error response",
+		body:        `{"foo": "bar"}`,
+		expectError: errors.New("response object is not an error response"),
+	}, {
+		desc:        "when the body is empty",
+		body:        "",
+		expectError: errors.New("EOF"),
+	}, {
+		desc:        "when the body is a truncated array",
+		body:        `[{"timestamp": 1}`,
+		expectError: errors.New("unexpected end of JSON input"),
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			forecasts, err := decodeForecasts(strings.NewReader(test.body), false)
+
+			if test.expectError == nil && err != nil {
+				t.Errorf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if test.expectError != nil && (err == nil || test.expectError.Error() != err.Error()) {
+				t.Errorf("expected error '%s'; got '%v'", test.expectError.Error(), err)
+			}
+
+			if len(forecasts) != test.expectForecastCount {
+				t.Errorf("expected '%d' forecasts; got '%d'", test.expectForecastCount, len(forecasts))
+			}
+		})
+	}
+}
+
+func TestDecodeForecasts_apiError(t *testing.T) {
+	_, err := decodeForecasts(strings.NewReader(errorResp), false)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError; got '%T'", err)
+	}
+
+	if apiErr.ErrorResponse.Code != 115 {
+		t.Errorf("expected error code '115'; got '%d'", apiErr.ErrorResponse.Code)
+	}
+}
+
+// largeResponse returns a forecast response consisting of n copies of the
+// testdata/response.json forecasts.
+func largeResponse(b *testing.B, n int) string {
+	b.Helper()
+
+	var fs []json.RawMessage
+	if err := json.Unmarshal([]byte(resp), &fs); err != nil {
+		b.Fatal(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < n; i++ {
+		for j, f := range fs {
+			if i > 0 || j > 0 {
+				sb.WriteString(",")
+			}
+			sb.Write(f)
+		}
+	}
+	sb.WriteString("]")
+
+	return sb.String()
+}
+
+// decodeForecastsReadAll is the buffering decoder decodeForecasts replaced;
+// it is retained as a benchmark baseline.
+func decodeForecastsReadAll(b *testing.B, body string) {
+	content, err := ioutil.ReadAll(strings.NewReader(body))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if strings.Contains(string(content), "error_response") {
+		var errResp APIError
+		if err := json.Unmarshal(content, &errResp); err != nil {
+			b.Fatal(err)
+		}
+
+		return
+	}
+
+	forecasts := []Forecast{}
+	if err := json.Unmarshal(content, &forecasts); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkDecodeForecasts compares decodeForecasts with the buffering decoder
+// it replaced. Streaming allocates a fraction of the bytes, as the body is
+// never held in full, for a comparable number of allocations; the error path,
+// being small, costs a few allocations more to stream than to buffer.
+func BenchmarkDecodeForecasts(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		body := largeResponse(b, n)
+
+		b.Run(fmt.Sprintf("stream/%d", n*3), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeForecasts(strings.NewReader(body), false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("readall/%d", n*3), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				decodeForecastsReadAll(b, body)
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeForecasts_error(b *testing.B) {
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeForecasts(strings.NewReader(errorResp), false); err == nil {
+				b.Fatal("expected an error")
+			}
+		}
+	})
+
+	b.Run("readall", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			decodeForecastsReadAll(b, errorResp)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package seaweed
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ChangeKind classifies a Change between two forecast issues.
+type ChangeKind string
+
+const (
+	// RatingUpgrade is a timestep's star rating increasing.
+	RatingUpgrade ChangeKind = "rating_upgrade"
+	// RatingDowngrade is a timestep's star rating decreasing.
+	RatingDowngrade ChangeKind = "rating_downgrade"
+	// SwellIncrease is a timestep's maximum breaking height increasing.
+	SwellIncrease ChangeKind = "swell_increase"
+	// SwellDecrease is a timestep's maximum breaking height decreasing.
+	SwellDecrease ChangeKind = "swell_decrease"
+	// WindIncrease is a timestep's wind speed increasing.
+	WindIncrease ChangeKind = "wind_increase"
+	// WindDecrease is a timestep's wind speed decreasing.
+	WindDecrease ChangeKind = "wind_decrease"
+	// WindShift is a timestep's wind direction changing.
+	WindShift ChangeKind = "wind_shift"
+)
+
+// Change is a difference between two issues of the same forecast timestep.
+type Change struct {
+	// Kind classifies the change.
+	Kind ChangeKind
+	// Old is the timestep as previously forecast.
+	Old Forecast
+	// New is the timestep as newly forecast.
+	New Forecast
+}
+
+// IssueDelta returns the time elapsed between the issue of the old and new
+// forecasts.
+func (c Change) IssueDelta() time.Duration {
+	return time.Duration(c.New.IssueTimestamp-c.Old.IssueTimestamp) * time.Second
+}
+
+// String renders the change for humans, such as:
+//
+//	Sat Mar 4 07:00: rating upgraded from ★☆ to ★★★
+func (c Change) String() string {
+	when := time.Unix(int64(c.New.LocalTimestamp), 0).UTC().Format("Mon Jan 2 15:04")
+
+	var what string
+	switch c.Kind {
+	case RatingUpgrade, RatingDowngrade:
+		verb := "upgraded"
+		if c.Kind == RatingDowngrade {
+			verb = "downgraded"
+		}
+		what = fmt.Sprintf("rating %s from %s to %s", verb, Stars(c.Old), Stars(c.New))
+	case SwellIncrease, SwellDecrease:
+		verb := "up"
+		if c.Kind == SwellDecrease {
+			verb = "down"
+		}
+		what = fmt.Sprintf("surf %s from %s to %s", verb, breakingHeight(c.Old), breakingHeight(c.New))
+	case WindIncrease, WindDecrease:
+		verb := "up"
+		if c.Kind == WindDecrease {
+			verb = "down"
+		}
+		what = fmt.Sprintf("wind %s from %d%s to %d%s", verb, c.Old.Wind.Speed, c.Old.Wind.Unit, c.New.Wind.Speed, c.New.Wind.Unit)
+	case WindShift:
+		what = fmt.Sprintf("wind shifted from %s to %s", c.Old.Wind.CompassDirection, c.New.Wind.CompassDirection)
+	default:
+		what = string(c.Kind)
+	}
+
+	return when + ": " + what
+}
+
+// Changes are the changes between two forecast issues.
+type Changes struct {
+	// OldIssued and NewIssued are the times at which the old and new issues
+	// were issued: the most recent IssueTimestamp among each issue's
+	// forecasts, or the zero time if there is none.
+	OldIssued, NewIssued time.Time
+	// Timesteps are the changes between the issues' forecasts of the same
+	// timesteps, ordered by timestamp.
+	Timesteps []Change
+}
+
+// IssueDelta returns the time elapsed between the issue of the old and new
+// forecasts.
+func (cs Changes) IssueDelta() time.Duration {
+	return cs.NewIssued.Sub(cs.OldIssued)
+}
+
+// String renders the changes for humans, one per line, preceded by the change
+// in issue time, if any.
+func (cs Changes) String() string {
+	var b strings.Builder
+	if !cs.OldIssued.IsZero() && !cs.NewIssued.IsZero() && !cs.OldIssued.Equal(cs.NewIssued) {
+		fmt.Fprintf(&b, "Forecast issued %s (previously %s)\n", formatIssue(cs.NewIssued), formatIssue(cs.OldIssued))
+	}
+
+	if len(cs.Timesteps) == 0 {
+		b.WriteString("No changes")
+
+		return b.String()
+	}
+
+	for _, c := range cs.Timesteps {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func formatIssue(t time.Time) string {
+	return t.UTC().Format("Jan 2 15:04 MST")
+}
+
+// issued returns the most recent IssueTimestamp among forecasts, or the zero
+// time if there is none.
+func issued(forecasts []Forecast) time.Time {
+	ts := latestIssue(forecasts)
+	if ts == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(ts, 0).UTC()
+}
+
+func breakingHeight(f Forecast) string {
+	return fmt.Sprintf("%d-%d%s", f.Swell.MinBreakingHeight, f.Swell.MaxBreakingHeight, f.Swell.Unit)
+}
+
+// diffOptions are the thresholds beyond which Diff reports changes.
+type diffOptions struct {
+	swellHeight   float64
+	windSpeed     int
+	windDirection float64
+}
+
+// DiffOption configures the thresholds beyond which Diff reports changes.
+type DiffOption = func(o *diffOptions)
+
+// WithSwellThreshold is a DiffOption configuring the minimum change in
+// absolute maximum breaking height, in the forecasts' Swell.Unit, Diff
+// reports. It defaults to 1.
+func WithSwellThreshold(height float64) DiffOption {
+	return func(o *diffOptions) {
+		o.swellHeight = height
+	}
+}
+
+// WithWindThreshold is a DiffOption configuring the minimum change in wind
+// speed, in the forecasts' Wind.Unit, Diff reports. It defaults to 5.
+func WithWindThreshold(speed int) DiffOption {
+	return func(o *diffOptions) {
+		o.windSpeed = speed
+	}
+}
+
+// WithWindShiftThreshold is a DiffOption configuring the minimum change in
+// wind direction, in degrees, Diff reports. It defaults to 45.
+func WithWindShiftThreshold(degrees float64) DiffOption {
+	return func(o *diffOptions) {
+		o.windDirection = degrees
+	}
+}
+
+// Diff compares two issues of a spot's forecast, matching timesteps by
+// Timestamp, and returns the changes between them: the issues' issue times,
+// and for each timestep any change in star rating, and changes in breaking
+// height, wind speed, and wind direction at or beyond the configured
+// thresholds. Timesteps present in only one issue are ignored.
+func Diff(old, new []Forecast, opts ...DiffOption) Changes {
+	o := &diffOptions{
+		swellHeight:   1,
+		windSpeed:     5,
+		windDirection: 45,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	previous := make(map[FlexInt64]Forecast, len(old))
+	for _, f := range old {
+		previous[f.Timestamp] = f
+	}
+
+	changes := Changes{OldIssued: issued(old), NewIssued: issued(new)}
+	for _, n := range new {
+		p, ok := previous[n.Timestamp]
+		if !ok {
+			continue
+		}
+
+		add := func(kind ChangeKind) {
+			changes.Timesteps = append(changes.Timesteps, Change{Kind: kind, Old: p, New: n})
+		}
+
+		if r := compareRatings(p, n); r > 0 {
+			add(RatingUpgrade)
+		} else if r < 0 {
+			add(RatingDowngrade)
+		}
+
+		if d := float64(n.Swell.AbsMaxBreakingHeight - p.Swell.AbsMaxBreakingHeight); d != 0 && math.Abs(d) >= o.swellHeight {
+			if d > 0 {
+				add(SwellIncrease)
+			} else {
+				add(SwellDecrease)
+			}
+		}
+
+		if d := int(n.Wind.Speed - p.Wind.Speed); d != 0 && abs(d) >= o.windSpeed {
+			if d > 0 {
+				add(WindIncrease)
+			} else {
+				add(WindDecrease)
+			}
+		}
+
+		shift := math.Abs(p.Wind.Toward().Difference(n.Wind.Toward()))
+		if shift > 0 && shift >= o.windDirection {
+			add(WindShift)
+		}
+	}
+
+	return changes
+}
+
+// compareRatings returns a positive number if b is rated higher than a, a
+// negative number if b is rated lower, and 0 otherwise. Solid stars outrank
+// faded stars.
+func compareRatings(a, b Forecast) int {
+	if a.SolidRating != b.SolidRating {
+		return int(b.SolidRating - a.SolidRating)
+	}
+
+	return int(b.FadedRating - a.FadedRating)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,162 @@
+package seaweed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func diffForecast(issued int64, solid, faded FlexInt, height FlexFloat, speed FlexInt, direction FlexInt64, compass string) Forecast {
+	return Forecast{
+		Timestamp:      1677931200, // Sat 2023-03-04 12:00 UTC
+		LocalTimestamp: 1677913200, // Sat 2023-03-04 07:00 local
+		IssueTimestamp: FlexInt64(issued),
+		SolidRating:    solid,
+		FadedRating:    faded,
+		Swell: Swell{
+			AbsMaxBreakingHeight: height,
+			MinBreakingHeight:    FlexInt(height) - 1,
+			MaxBreakingHeight:    FlexInt(height),
+			Unit:                 "ft",
+		},
+		Wind: Wind{Speed: speed, Direction: direction, CompassDirection: compass, Unit: "mph"},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := diffForecast(1677909600, 1, 1, 3, 10, 0, "S")
+
+	tests := []struct {
+		desc   string
+		new    Forecast
+		opts   []DiffOption
+		expect []ChangeKind
+	}{{
+		desc: "no changes",
+		new:  diffForecast(1677931200, 1, 1, 3, 10, 0, "S"),
+	}, {
+		desc:   "rating upgrade",
+		new:    diffForecast(1677931200, 2, 0, 3, 10, 0, "S"),
+		expect: []ChangeKind{RatingUpgrade},
+	}, {
+		desc:   "rating downgrade",
+		new:    diffForecast(1677931200, 1, 0, 3, 10, 0, "S"),
+		expect: []ChangeKind{RatingDowngrade},
+	}, {
+		desc: "swell change within the default threshold",
+		new:  diffForecast(1677931200, 1, 1, 3.5, 10, 0, "S"),
+	}, {
+		desc:   "swell change within a configured threshold",
+		new:    diffForecast(1677931200, 1, 1, 3.5, 10, 0, "S"),
+		opts:   []DiffOption{WithSwellThreshold(0.5)},
+		expect: []ChangeKind{SwellIncrease},
+	}, {
+		desc: "no changes with zero thresholds",
+		new:  diffForecast(1677931200, 1, 1, 3, 10, 0, "S"),
+		opts: []DiffOption{WithSwellThreshold(0), WithWindThreshold(0), WithWindShiftThreshold(0)},
+	}, {
+		desc:   "swell change with a zero threshold",
+		new:    diffForecast(1677931200, 1, 1, 3.1, 10, 0, "S"),
+		opts:   []DiffOption{WithSwellThreshold(0)},
+		expect: []ChangeKind{SwellIncrease},
+	}, {
+		desc:   "swell decrease",
+		new:    diffForecast(1677931200, 1, 1, 1, 10, 0, "S"),
+		expect: []ChangeKind{SwellDecrease},
+	}, {
+		desc:   "wind increase and shift",
+		new:    diffForecast(1677931200, 1, 1, 3, 20, 315, "SE"),
+		expect: []ChangeKind{WindIncrease, WindShift},
+	}, {
+		desc: "wind change within configured thresholds",
+		new:  diffForecast(1677931200, 1, 1, 3, 5, 315, "SE"),
+		opts: []DiffOption{WithWindThreshold(10), WithWindShiftThreshold(90)},
+	}, {
+		desc:   "wind decrease",
+		new:    diffForecast(1677931200, 1, 1, 3, 5, 0, "S"),
+		expect: []ChangeKind{WindDecrease},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var got []ChangeKind
+			for _, c := range Diff([]Forecast{old}, []Forecast{test.new}, test.opts...).Timesteps {
+				got = append(got, c.Kind)
+			}
+
+			if !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("expected '%v'; got '%v'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestDiffUnmatchedTimesteps(t *testing.T) {
+	old := diffForecast(1677909600, 1, 1, 3, 10, 0, "S")
+	new := diffForecast(1677931200, 4, 0, 3, 10, 0, "S")
+	new.Timestamp += 3 * 60 * 60
+
+	if got := Diff([]Forecast{old}, []Forecast{new}); len(got.Timesteps) != 0 {
+		t.Errorf("expected no changes; got '%v'", got.Timesteps)
+	}
+}
+
+func TestChangeIssueDelta(t *testing.T) {
+	changes := Diff(
+		[]Forecast{diffForecast(1677909600, 1, 1, 3, 10, 0, "S")},
+		[]Forecast{diffForecast(1677931200, 2, 1, 3, 10, 0, "S")},
+	)
+
+	if got := changes.Timesteps[0].IssueDelta(); got != 6*time.Hour {
+		t.Errorf("expected '%s'; got '%s'", 6*time.Hour, got)
+	}
+
+	if got := changes.IssueDelta(); got != 6*time.Hour {
+		t.Errorf("expected '%s'; got '%s'", 6*time.Hour, got)
+	}
+}
+
+func TestChangesString(t *testing.T) {
+	tests := []struct {
+		desc    string
+		changes Changes
+		expect  string
+	}{{
+		desc:   "no changes",
+		expect: "No changes",
+	}, {
+		desc: "changes",
+		changes: Diff(
+			[]Forecast{diffForecast(1677909600, 1, 1, 3, 10, 0, "S")},
+			[]Forecast{diffForecast(1677931200, 3, 0, 5, 20, 315, "SE")},
+		),
+		expect: "Forecast issued Mar 4 12:00 UTC (previously Mar 4 06:00 UTC)\n" +
+			"Sat Mar 4 07:00: rating upgraded from ★☆ to ★★★\n" +
+			"Sat Mar 4 07:00: surf up from 2-3ft to 4-5ft\n" +
+			"Sat Mar 4 07:00: wind up from 10mph to 20mph\n" +
+			"Sat Mar 4 07:00: wind shifted from S to SE\n",
+	}, {
+		desc: "same issue",
+		changes: Diff(
+			[]Forecast{diffForecast(1677909600, 1, 1, 3, 10, 0, "S")},
+			[]Forecast{diffForecast(1677909600, 1, 0, 3, 10, 0, "S")},
+		),
+		expect: "Sat Mar 4 07:00: rating downgraded from ★☆ to ★\n",
+	}, {
+		desc: "new issue without changes",
+		changes: Diff(
+			[]Forecast{diffForecast(1677909600, 1, 1, 3, 10, 0, "S")},
+			[]Forecast{diffForecast(1677931200, 1, 1, 3, 10, 0, "S")},
+		),
+		expect: "Forecast issued Mar 4 12:00 UTC (previously Mar 4 06:00 UTC)\n" +
+			"No changes",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.changes.String(); got != test.expect {
+				t.Errorf("expected '%s'; got '%s'", test.expect, got)
+			}
+		})
+	}
+}
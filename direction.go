@@ -0,0 +1,124 @@
+package seaweed
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Direction is a compass bearing, in degrees clockwise from true north.
+//
+// The Magic Seaweed API reports swell and wind directions as the bearing they
+// travel toward, whereas surfers, and the API's own compass directions, speak
+// of the bearing they come from. Component and Wind expose both explicitly;
+// see Component.From and Component.Toward.
+type Direction float64
+
+// compass16 are the 16 points of the compass, clockwise from north.
+var compass16 = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// compass32 are the 32 points of the compass, clockwise from north, in which
+// "b" abbreviates "by", as in NbE, north by east.
+var compass32 = []string{
+	"N", "NbE", "NNE", "NEbN", "NE", "NEbE", "ENE", "EbN",
+	"E", "EbS", "ESE", "SEbE", "SE", "SEbS", "SSE", "SbE",
+	"S", "SbW", "SSW", "SWbS", "SW", "SWbW", "WSW", "WbS",
+	"W", "WbN", "WNW", "NWbW", "NW", "NWbN", "NNW", "NbW",
+}
+
+// ParseCompass returns the Direction of a 16 or 32-point compass name, such
+// as "SSE" or "NbE". Names are case insensitive.
+func ParseCompass(name string) (Direction, error) {
+	for i, point := range compass32 {
+		if strings.EqualFold(name, point) {
+			return Direction(float64(i) * 360 / float64(len(compass32))), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid compass direction %q", name)
+}
+
+// Normalize returns the direction expressed within [0, 360).
+func (d Direction) Normalize() Direction {
+	return Direction(normalizeDegrees(float64(d)))
+}
+
+// Opposite returns the direction 180 degrees from d: the direction from which
+// something travelling toward d comes, and vice versa.
+func (d Direction) Opposite() Direction {
+	return (d + 180).Normalize()
+}
+
+// Compass returns the name of the 16-point compass direction nearest d, such
+// as "SSE".
+func (d Direction) Compass() string {
+	return compassPoint(d, compass16)
+}
+
+// Compass32 returns the name of the 32-point compass direction nearest d, such
+// as "SbE".
+func (d Direction) Compass32() string {
+	return compassPoint(d, compass32)
+}
+
+func compassPoint(d Direction, points []string) string {
+	step := 360 / float64(len(points))
+
+	return points[int(math.Round(float64(d.Normalize())/step))%len(points)]
+}
+
+// Difference returns the signed angle, in degrees within (-180, 180], through
+// which d turns clockwise to reach to along the shorter arc. For example,
+// Direction(350).Difference(10) is 20, and Direction(10).Difference(350) is
+// -20.
+func (d Direction) Difference(to Direction) float64 {
+	return 180 - normalizeDegrees(180-float64(to-d))
+}
+
+// String returns the direction in degrees alongside its 16-point compass name,
+// such as "157° SSE".
+func (d Direction) String() string {
+	return fmt.Sprintf("%g° %s", math.Round(float64(d.Normalize())), d.Compass())
+}
+
+// MeanDirection returns the circular mean of the directions, such that the
+// mean of 350 and 10 is 0 rather than 180. It returns false if there are no
+// directions, or if they cancel out, such as 90 and 270.
+func MeanDirection(directions ...Direction) (Direction, bool) {
+	var x, y float64
+	for _, d := range directions {
+		rad := float64(d) * math.Pi / 180
+		x += math.Cos(rad)
+		y += math.Sin(rad)
+	}
+
+	if math.Hypot(x, y) < 1e-9 {
+		return 0, false
+	}
+
+	return Direction(math.Atan2(y, x) * 180 / math.Pi).Normalize(), true
+}
+
+// Toward returns the direction toward which the swell component travels, as
+// the API reports it.
+func (c Component) Toward() Direction {
+	return Direction(c.Direction).Normalize()
+}
+
+// From returns the direction from which the swell component comes, as its
+// CompassDirection names it.
+func (c Component) From() Direction {
+	return Direction(c.Direction).Opposite()
+}
+
+// Toward returns the direction toward which the wind blows, as the API
+// reports it.
+func (w Wind) Toward() Direction {
+	return Direction(w.Direction).Normalize()
+}
+
+// From returns the direction from which the wind blows, as its
+// CompassDirection names it.
+func (w Wind) From() Direction {
+	return Direction(w.Direction).Opposite()
+}
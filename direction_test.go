@@ -0,0 +1,210 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseCompass(t *testing.T) {
+	tests := []struct {
+		name        string
+		expect      Direction
+		expectError string
+	}{
+		{name: "N", expect: 0},
+		{name: "SSE", expect: 157.5},
+		{name: "wnw", expect: 292.5},
+		{name: "NbE", expect: 11.25},
+		{name: "SWbW", expect: 236.25},
+		{name: "NbW", expect: 348.75},
+		{name: "NNNE", expectError: `invalid compass direction "NNNE"`},
+		{name: "", expectError: `invalid compass direction ""`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ParseCompass(test.name)
+
+			if test.expectError != "" {
+				if err == nil || err.Error() != test.expectError {
+					t.Errorf("expected error '%s'; got '%v'", test.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error; got '%v'", err)
+			}
+
+			if d != test.expect {
+				t.Errorf("expected '%g'; got '%g'", test.expect, d)
+			}
+		})
+	}
+}
+
+func TestDirection_Compass(t *testing.T) {
+	tests := []struct {
+		degrees  Direction
+		expect   string
+		expect32 string
+	}{
+		{0, "N", "N"},
+		{11.24, "N", "NbE"},
+		{11.26, "NNE", "NbE"},
+		{5.62, "N", "N"},
+		{5.63, "N", "NbE"},
+		{180, "S", "S"},
+		{348.76, "N", "NbW"},
+		{-90, "W", "W"},
+		{720, "N", "N"},
+	}
+
+	for _, test := range tests {
+		if got := test.degrees.Compass(); got != test.expect {
+			t.Errorf("expected '%g' to be '%s'; got '%s'", test.degrees, test.expect, got)
+		}
+
+		if got := test.degrees.Compass32(); got != test.expect32 {
+			t.Errorf("expected '%g' to be '%s' on the 32-point compass; got '%s'", test.degrees, test.expect32, got)
+		}
+	}
+
+	// Every compass name round trips.
+	for _, name := range compass32 {
+		d, err := ParseCompass(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := d.Compass32(); got != name {
+			t.Errorf("expected '%s' to round trip; got '%s'", name, got)
+		}
+	}
+}
+
+func TestDirection_Difference(t *testing.T) {
+	tests := []struct {
+		from, to Direction
+		expect   float64
+	}{
+		{0, 90, 90},
+		{90, 0, -90},
+		{350, 10, 20},
+		{10, 350, -20},
+		{0, 180, 180},
+		{180, 0, 180},
+		{-45, 45, 90},
+		{720, 30, 30},
+		{45, 45, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.from.Difference(test.to); got != test.expect {
+			t.Errorf("expected the difference from '%g' to '%g' to be '%g'; got '%g'", test.from, test.to, test.expect, got)
+		}
+	}
+}
+
+func TestMeanDirection(t *testing.T) {
+	tests := []struct {
+		desc       string
+		directions []Direction
+		expect     Direction
+		expectOK   bool
+	}{{
+		desc:       "spanning north",
+		directions: []Direction{350, 10},
+		expect:     0,
+		expectOK:   true,
+	}, {
+		desc:       "in one quadrant",
+		directions: []Direction{80, 90, 100},
+		expect:     90,
+		expectOK:   true,
+	}, {
+		desc:       "a single direction",
+		directions: []Direction{-90},
+		expect:     270,
+		expectOK:   true,
+	}, {
+		desc:       "opposing directions",
+		directions: []Direction{90, 270},
+	}, {
+		desc: "no directions",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, ok := MeanDirection(test.directions...)
+
+			if ok != test.expectOK {
+				t.Fatalf("expected ok '%v'; got '%v'", test.expectOK, ok)
+			}
+
+			if math.Abs(test.expect.Difference(got)) > 1e-9 {
+				t.Errorf("expected '%g'; got '%g'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestDirection_String(t *testing.T) {
+	if got := Direction(-202.6).String(); got != "157° SSE" {
+		t.Errorf("expected '157° SSE'; got '%s'", got)
+	}
+}
+
+func TestFromToward(t *testing.T) {
+	var forecasts []Forecast
+	if err := json.Unmarshal([]byte(resp), &forecasts); err != nil {
+		t.Fatal(err)
+	}
+
+	f := forecasts[0]
+
+	tests := []struct {
+		desc         string
+		from, toward Direction
+		compass      string
+	}{{
+		desc:    "combined swell",
+		from:    f.Swell.Components.Combined.From(),
+		toward:  f.Swell.Components.Combined.Toward(),
+		compass: f.Swell.Components.Combined.CompassDirection,
+	}, {
+		desc:    "primary swell",
+		from:    f.Swell.Components.Primary.From(),
+		toward:  f.Swell.Components.Primary.Toward(),
+		compass: f.Swell.Components.Primary.CompassDirection,
+	}, {
+		desc:    "wind",
+		from:    f.Wind.From(),
+		toward:  f.Wind.Toward(),
+		compass: f.Wind.CompassDirection,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			// The API's compass direction names the direction the swell or
+			// wind comes from.
+			if got := test.from.Compass(); got != test.compass {
+				t.Errorf("expected From to be '%s'; got '%s' (%g)", test.compass, got, test.from)
+			}
+
+			if got := test.from.Difference(test.toward); got != 180 {
+				t.Errorf("expected From and Toward to oppose; got a difference of '%g'", got)
+			}
+		})
+	}
+
+	if got := f.Swell.Components.Combined.From(); math.Abs(float64(got)-125.22) > 1e-9 {
+		t.Errorf("expected the combined swell from '125.22'; got '%g'", got)
+	}
+
+	if got := f.Wind.Toward(); got != 337 {
+		t.Errorf("expected the wind toward '337'; got '%g'", got)
+	}
+}
@@ -0,0 +1,176 @@
+package seaweed
+
+import (
+	"math"
+	"time"
+)
+
+// Predicate reports whether a Forecast satisfies a condition.
+type Predicate func(Forecast) bool
+
+// Filter returns the forecasts satisfying all of the predicates it's passed.
+func Filter(forecasts []Forecast, preds ...Predicate) []Forecast {
+	var filtered []Forecast
+	match := And(preds...)
+
+	for _, each := range forecasts {
+		if match(each) {
+			filtered = append(filtered, each)
+		}
+	}
+
+	return filtered
+}
+
+// And returns a Predicate satisfied by forecasts satisfying all of preds. And
+// with no predicates is satisfied by all forecasts.
+func And(preds ...Predicate) Predicate {
+	return func(f Forecast) bool {
+		for _, p := range preds {
+			if !p(f) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns a Predicate satisfied by forecasts satisfying any of preds. Or
+// with no predicates is satisfied by no forecasts.
+func Or(preds ...Predicate) Predicate {
+	return func(f Forecast) bool {
+		for _, p := range preds {
+			if p(f) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a Predicate satisfied by forecasts not satisfying p.
+func Not(p Predicate) Predicate {
+	return func(f Forecast) bool {
+		return !p(f)
+	}
+}
+
+// OnDay returns a Predicate satisfied by forecasts pertaining to the day of t;
+// see Forecast.IsDay.
+func OnDay(t time.Time) Predicate {
+	return func(f Forecast) bool {
+		return f.IsDay(t)
+	}
+}
+
+// OnWeekend returns a Predicate satisfied by forecasts pertaining to a
+// Saturday or a Sunday; see Forecast.IsWeekend.
+func OnWeekend() Predicate {
+	return Forecast.IsWeekend
+}
+
+// Between returns a Predicate satisfied by forecasts whose Timestamp falls
+// within start and end, inclusive. A zero start or end leaves the range open
+// on that side.
+func Between(start, end time.Time) Predicate {
+	return func(f Forecast) bool {
+		t := time.Unix(int64(f.Timestamp), 0)
+
+		if !start.IsZero() && t.Before(start) {
+			return false
+		}
+
+		if !end.IsZero() && t.After(end) {
+			return false
+		}
+
+		return true
+	}
+}
+
+// MinSolidRating returns a Predicate satisfied by forecasts with a
+// SolidRating of at least n stars.
+func MinSolidRating(n int) Predicate {
+	return func(f Forecast) bool {
+		return int(f.SolidRating) >= n
+	}
+}
+
+// MinPrimaryPeriod returns a Predicate satisfied by forecasts whose primary
+// swell period is at least seconds.
+func MinPrimaryPeriod(seconds int) Predicate {
+	return func(f Forecast) bool {
+		return int(f.Swell.Components.Primary.Period) >= seconds
+	}
+}
+
+// MaxWind returns a Predicate satisfied by forecasts whose wind speed is at
+// most speed, in the forecast's Wind.Unit.
+func MaxWind(speed int) Predicate {
+	return func(f Forecast) bool {
+		return int(f.Wind.Speed) <= speed
+	}
+}
+
+// DirectionRange is an arc of compass directions, in degrees, running
+// clockwise from From to To. An arc may span north; {From: 315, To: 45}
+// includes 0, and {From: 0, To: 360} includes all directions.
+type DirectionRange struct {
+	From float64
+	To   float64
+}
+
+// Contains returns true if the direction d, in degrees, falls within the
+// range, inclusive.
+func (r DirectionRange) Contains(d float64) bool {
+	if r.To-r.From >= 360 {
+		return true
+	}
+
+	from, to, d := normalizeDegrees(r.From), normalizeDegrees(r.To), normalizeDegrees(d)
+	if from <= to {
+		return d >= from && d <= to
+	}
+
+	return d >= from || d <= to
+}
+
+// normalizeDegrees returns d expressed within [0, 360).
+func normalizeDegrees(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+
+	return d
+}
+
+// SwellFrom returns a Predicate satisfied by forecasts whose primary swell
+// comes from a direction within r; see Component.From.
+func SwellFrom(r DirectionRange) Predicate {
+	return func(f Forecast) bool {
+		return r.Contains(float64(f.Swell.Components.Primary.From()))
+	}
+}
+
+// SwellToward returns a Predicate satisfied by forecasts whose primary swell
+// travels toward a direction within r, as the API reports it; see
+// Component.Toward.
+func SwellToward(r DirectionRange) Predicate {
+	return func(f Forecast) bool {
+		return r.Contains(float64(f.Swell.Components.Primary.Toward()))
+	}
+}
+
+// Daylight returns a Predicate satisfied by forecasts whose local time falls
+// between the hours of firstHour and lastHour, inclusive, such as
+// Daylight(6, 18).
+func Daylight(firstHour, lastHour int) Predicate {
+	return func(f Forecast) bool {
+		h := time.Unix(int64(f.LocalTimestamp), 0).UTC().Hour()
+
+		return h >= firstHour && h <= lastHour
+	}
+}
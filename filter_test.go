@@ -0,0 +1,143 @@
+package seaweed
+
+import (
+	"testing"
+	"time"
+)
+
+func filterFixture() []Forecast {
+	return []Forecast{{
+		Timestamp:      1677931200, // Sat 2023-03-04 12:00 UTC
+		LocalTimestamp: 1677913200, // Sat 2023-03-04 07:00 local
+		SolidRating:    3,
+		Swell:          Swell{Components: Components{Primary: Component{Period: 12, Direction: 350}}},
+		Wind:           Wind{Speed: 8},
+	}, {
+		Timestamp:      1677942000, // Sat 2023-03-04 15:00 UTC
+		LocalTimestamp: 1677924000, // Sat 2023-03-04 10:00 local
+		SolidRating:    1,
+		Swell:          Swell{Components: Components{Primary: Component{Period: 8, Direction: 90}}},
+		Wind:           Wind{Speed: 15},
+	}, {
+		Timestamp:      1678003200, // Sun 2023-03-05 08:00 UTC
+		LocalTimestamp: 1677985200, // Sun 2023-03-05 03:00 local
+		SolidRating:    4,
+		Swell:          Swell{Components: Components{Primary: Component{Period: 14, Direction: 10}}},
+		Wind:           Wind{Speed: 5},
+	}}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		desc   string
+		preds  []Predicate
+		expect []int64
+	}{{
+		desc:   "no predicates",
+		expect: []int64{1677931200, 1677942000, 1678003200},
+	}, {
+		desc:   "MinSolidRating",
+		preds:  []Predicate{MinSolidRating(3)},
+		expect: []int64{1677931200, 1678003200},
+	}, {
+		desc:   "MinPrimaryPeriod and MaxWind",
+		preds:  []Predicate{MinPrimaryPeriod(12), MaxWind(5)},
+		expect: []int64{1678003200},
+	}, {
+		desc:   "Between",
+		preds:  []Predicate{Between(time.Unix(1677942000, 0), time.Unix(1678003200, 0))},
+		expect: []int64{1677942000, 1678003200},
+	}, {
+		desc:   "Between with an open end",
+		preds:  []Predicate{Between(time.Unix(1677942001, 0), time.Time{})},
+		expect: []int64{1678003200},
+	}, {
+		desc:   "SwellFrom",
+		preds:  []Predicate{SwellFrom(DirectionRange{From: 135, To: 225})},
+		expect: []int64{1677931200, 1678003200},
+	}, {
+		desc:   "SwellFrom spanning north",
+		preds:  []Predicate{SwellFrom(DirectionRange{From: 225, To: 315})},
+		expect: []int64{1677942000},
+	}, {
+		desc:   "SwellToward spanning north",
+		preds:  []Predicate{SwellToward(DirectionRange{From: 315, To: 45})},
+		expect: []int64{1677931200, 1678003200},
+	}, {
+		desc:   "Daylight",
+		preds:  []Predicate{Daylight(6, 18)},
+		expect: []int64{1677931200, 1677942000},
+	}, {
+		desc:   "OnDay",
+		preds:  []Predicate{OnDay(time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC))},
+		expect: []int64{1678003200},
+	}, {
+		desc:   "Or",
+		preds:  []Predicate{Or(MinSolidRating(4), MaxWind(8))},
+		expect: []int64{1677931200, 1678003200},
+	}, {
+		desc:   "Not",
+		preds:  []Predicate{Not(Or(MinSolidRating(4), MaxWind(8)))},
+		expect: []int64{1677942000},
+	}, {
+		desc:  "Or with no predicates",
+		preds: []Predicate{Or()},
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filtered := Filter(filterFixture(), test.preds...)
+
+			if len(filtered) != len(test.expect) {
+				t.Fatalf("expected '%d' forecasts; got '%d'", len(test.expect), len(filtered))
+			}
+
+			for i, ts := range test.expect {
+				if int64(filtered[i].Timestamp) != ts {
+					t.Errorf("expected forecast %d Timestamp '%d'; got '%d'", i, ts, filtered[i].Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestDirectionRange_Contains(t *testing.T) {
+	tests := []struct {
+		r      DirectionRange
+		d      float64
+		expect bool
+	}{
+		{DirectionRange{90, 180}, 90, true},
+		{DirectionRange{90, 180}, 180, true},
+		{DirectionRange{90, 180}, 181, false},
+		{DirectionRange{315, 45}, 0, true},
+		{DirectionRange{315, 45}, 360, true},
+		{DirectionRange{315, 45}, 180, false},
+		{DirectionRange{-45, 45}, 350, true},
+		{DirectionRange{0, 360}, 270, true},
+	}
+
+	for _, test := range tests {
+		if got := test.r.Contains(test.d); got != test.expect {
+			t.Errorf("expected %v.Contains(%v) to be '%t'; got '%t'", test.r, test.d, test.expect, got)
+		}
+	}
+}
+
+func TestClient_Filtered(t *testing.T) {
+	server, c := testServerAndClient(200, resp)
+	defer server.Close()
+
+	forecasts, err := c.Filtered("123", OnWeekend(), MinPrimaryPeriod(10))
+	if err != nil {
+		t.Fatalf("expected Filtered not to error; got '%v'", err)
+	}
+
+	if len(forecasts) != 1 || forecasts[0].LocalTimestamp != 1677973254 {
+		t.Errorf("expected the weekend forecast; got '%v'", forecasts)
+	}
+}
@@ -0,0 +1,115 @@
+package seaweed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The Magic Seaweed API is inconsistent in how it types numeric fields: a
+// field may arrive as a JSON number, a numeric string, or null. The Flex types
+// decode any of these without error, such that type drift in the API never
+// causes an otherwise usable response to be rejected. Null, empty, and
+// non-numeric strings decode to the zero value.
+
+// FlexInt is an int decoded from a JSON number, string, or null.
+type FlexInt int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *FlexInt) UnmarshalJSON(b []byte) error {
+	f, err := parseFlexNumber(b)
+	if err != nil {
+		return err
+	}
+
+	*i = FlexInt(math.Round(f))
+
+	return nil
+}
+
+// FlexInt64 is an int64 decoded from a JSON number, string, or null.
+type FlexInt64 int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *FlexInt64) UnmarshalJSON(b []byte) error {
+	f, err := parseFlexNumber(b)
+	if err != nil {
+		return err
+	}
+
+	*i = FlexInt64(math.Round(f))
+
+	return nil
+}
+
+// FlexFloat is a float64 decoded from a JSON number, string, or null.
+type FlexFloat float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *FlexFloat) UnmarshalJSON(b []byte) error {
+	v, err := parseFlexNumber(b)
+	if err != nil {
+		return err
+	}
+
+	*f = FlexFloat(v)
+
+	return nil
+}
+
+// FlexString is a string decoded from a JSON string, number, or null.
+type FlexString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FlexString) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+
+	switch {
+	case bytes.Equal(b, []byte("null")):
+		*s = ""
+	case len(b) > 0 && b[0] == '"':
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = FlexString(v)
+	case len(b) > 0 && (b[0] == '-' || (b[0] >= '0' && b[0] <= '9')):
+		*s = FlexString(b)
+	default:
+		return fmt.Errorf("cannot decode %s into a string", b)
+	}
+
+	return nil
+}
+
+// parseFlexNumber parses a JSON number, string, or null. Null, empty, and
+// non-numeric strings parse as 0. Other JSON values are an error.
+func parseFlexNumber(b []byte) (float64, error) {
+	b = bytes.TrimSpace(b)
+
+	switch {
+	case bytes.Equal(b, []byte("null")):
+		return 0, nil
+	case len(b) > 0 && b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, err
+		}
+
+		f, err := strconv.ParseFloat(string(bytes.TrimSpace([]byte(s))), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, nil
+		}
+
+		return f, nil
+	default:
+		f, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot decode %s into a number", b)
+		}
+
+		return f, nil
+	}
+}
@@ -0,0 +1,185 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexNumbers(t *testing.T) {
+	tests := []struct {
+		desc        string
+		json        string
+		expectInt   FlexInt
+		expectInt64 FlexInt64
+		expectFloat FlexFloat
+		expectError bool
+	}{{
+		desc:        "when the value is a number",
+		json:        `12.5`,
+		expectInt:   13,
+		expectInt64: 13,
+		expectFloat: 12.5,
+	}, {
+		desc:        "when the value is a numeric string",
+		json:        `" 7 "`,
+		expectInt:   7,
+		expectInt64: 7,
+		expectFloat: 7,
+	}, {
+		desc:        "when the value is a quoted timestamp",
+		json:        `"1442355356"`,
+		expectInt:   1442355356,
+		expectInt64: 1442355356,
+		expectFloat: 1442355356,
+	}, {
+		desc: "when the value is null",
+		json: `null`,
+	}, {
+		desc: "when the value is an empty string",
+		json: `""`,
+	}, {
+		desc: "when the value is a non-numeric string",
+		json: `"n/a"`,
+	}, {
+		desc:        "when the value is a boolean",
+		json:        `true`,
+		expectError: true,
+	}, {
+		desc:        "when the value is an object",
+		json:        `{}`,
+		expectError: true,
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var v struct {
+				Int   FlexInt   `json:"int"`
+				Int64 FlexInt64 `json:"int64"`
+				Float FlexFloat `json:"float"`
+			}
+			body := `{"int":` + test.json + `,"int64":` + test.json + `,"float":` + test.json + `}`
+			err := json.Unmarshal([]byte(body), &v)
+
+			if test.expectError {
+				if err == nil {
+					t.Errorf("expected '%s' to error", test.json)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected '%s' not to error; got '%v'", test.json, err)
+			}
+
+			if v.Int != test.expectInt {
+				t.Errorf("expected FlexInt '%d'; got '%d'", test.expectInt, v.Int)
+			}
+
+			if v.Int64 != test.expectInt64 {
+				t.Errorf("expected FlexInt64 '%d'; got '%d'", test.expectInt64, v.Int64)
+			}
+
+			if v.Float != test.expectFloat {
+				t.Errorf("expected FlexFloat '%v'; got '%v'", test.expectFloat, v.Float)
+			}
+		})
+	}
+}
+
+func TestFlexString(t *testing.T) {
+	tests := []struct {
+		json        string
+		expect      FlexString
+		expectError bool
+	}{
+		{json: `"22"`, expect: "22"},
+		{json: `22`, expect: "22"},
+		{json: `null`, expect: ""},
+		{json: `[]`, expectError: true},
+	}
+
+	for _, test := range tests {
+		var s FlexString
+		err := json.Unmarshal([]byte(test.json), &s)
+
+		if test.expectError != (err != nil) {
+			t.Errorf("expected '%s' error to be '%t'; got '%v'", test.json, test.expectError, err)
+		}
+
+		if s != test.expect {
+			t.Errorf("expected '%s' to decode to '%s'; got '%s'", test.json, test.expect, s)
+		}
+	}
+}
+
+func TestForecast_lenientDecoding(t *testing.T) {
+	body := `{
+		"timestamp": "1442355356",
+		"localTimestamp": null,
+		"issueTimestamp": " 1442343600 ",
+		"fadedRating": "3",
+		"solidRating": null,
+		"swell": {
+			"minBreakingHeight": "5",
+			"absMaxBreakingHeight": 7.63,
+			"components": {
+				"combined": {"height": "7.5", "period": 10, "direction": 305.22},
+				"primary": {"height": 0, "period": "10", "direction": "309.5"},
+				"secondary": {"height": 0, "period": 0, "direction": 0}
+			}
+		},
+		"wind": {"speed": "13", "gusts": null},
+		"condition": {"pressure": "1008", "weather": 22}
+	}`
+
+	var f Forecast
+	if err := json.Unmarshal([]byte(body), &f); err != nil {
+		t.Fatalf("expected lenient decoding not to error; got '%v'", err)
+	}
+
+	if f.Timestamp != 1442355356 || f.LocalTimestamp != 0 || f.IssueTimestamp != 1442343600 {
+		t.Errorf("expected timestamps '1442355356', '0' and '1442343600'; got '%d', '%d' and '%d'", f.Timestamp, f.LocalTimestamp, f.IssueTimestamp)
+	}
+
+	if f.FadedRating != 3 || f.SolidRating != 0 {
+		t.Errorf("expected ratings '3' and '0'; got '%d' and '%d'", f.FadedRating, f.SolidRating)
+	}
+
+	if f.Swell.MinBreakingHeight != 5 || f.Swell.Components.Combined.Height != 7.5 {
+		t.Errorf("expected swell heights '5' and '7.5'; got '%d' and '%v'", f.Swell.MinBreakingHeight, f.Swell.Components.Combined.Height)
+	}
+
+	if f.Swell.Components.Primary.Period != 10 || f.Swell.Components.Primary.Direction != 309.5 {
+		t.Errorf("expected primary period '10' and direction '309.5'; got '%d' and '%v'", f.Swell.Components.Primary.Period, f.Swell.Components.Primary.Direction)
+	}
+
+	if f.Swell.Components.Secondary == nil {
+		t.Error("expected a zero-valued secondary component to be present")
+	}
+
+	if f.Swell.Components.Tertiary != nil {
+		t.Error("expected an absent tertiary component to be nil")
+	}
+
+	if f.Wind.Speed != 13 || f.Condition.Pressure != 1008 || f.Condition.Weather != "22" {
+		t.Errorf("expected wind speed '13', pressure '1008' and weather '22'; got '%d', '%d' and '%s'", f.Wind.Speed, f.Condition.Pressure, f.Condition.Weather)
+	}
+
+	encoded, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var roundTripped Forecast
+	if err := json.Unmarshal(encoded, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+
+	if roundTripped.Swell.Components.Secondary == nil || roundTripped.Swell.Components.Tertiary != nil {
+		t.Error("expected component presence to survive a JSON round trip")
+	}
+}
@@ -0,0 +1,42 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content)); err != nil {
+			t.Fatalf("expected no error; got '%v'", err)
+		}
+
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != content {
+			t.Errorf("expected '%s'; got '%s'", content, got)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to remain; got '%d' files", len(entries))
+	}
+}
+
+func TestWriteFile_missingDir(t *testing.T) {
+	if err := WriteFile(filepath.Join(t.TempDir(), "missing", "state.json"), []byte("{}")); err == nil {
+		t.Error("expected an error writing to a missing directory")
+	}
+}
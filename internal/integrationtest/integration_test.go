@@ -80,7 +80,7 @@ func TestToday_Integration(t *testing.T) {
 	today := time.Now().UTC()
 
 	for _, forecast := range resp {
-		fd := time.Unix(forecast.LocalTimestamp, 0).UTC()
+		fd := time.Unix(int64(forecast.LocalTimestamp), 0).UTC()
 
 		if fd.Day() != today.Day() {
 			t.Errorf("Today returned forecast for '%s'", fd.String())
@@ -101,7 +101,7 @@ func TestTomorrow_Integration(t *testing.T) {
 	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
 
 	for _, forecast := range resp {
-		fd := time.Unix(forecast.LocalTimestamp, 0).UTC()
+		fd := time.Unix(int64(forecast.LocalTimestamp), 0).UTC()
 
 		if fd.Day() != tomorrow.Day() {
 			t.Errorf("Tomorrow returned forecast for '%s'", fd.String())
@@ -116,7 +116,7 @@ func TestWeekend_Integration(t *testing.T) {
 	}
 
 	for _, forecast := range resp {
-		fd := time.Unix(forecast.LocalTimestamp, 0).UTC().Weekday().String()
+		fd := time.Unix(int64(forecast.LocalTimestamp), 0).UTC().Weekday().String()
 
 		if fd != "Saturday" && fd != "Sunday" {
 			t.Errorf("Weekend returned forecast for '%s'", fd)
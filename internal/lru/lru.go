@@ -0,0 +1,106 @@
+// Package lru provides a size-bounded cache evicting its least recently used
+// entries.
+package lru
+
+import "container/list"
+
+// Cache is a cache of at most a fixed number of entries, evicting the least
+// recently used entry to make room for another. A Cache is not safe for
+// concurrent use.
+type Cache[K comparable, V any] struct {
+	// max is the maximum number of entries.
+	max int
+	// order holds the entries, most recently used first.
+	order *list.List
+	// items holds the elements of order by key.
+	items map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// New returns an empty *Cache holding at most max entries. A max of less than
+// 1 is treated as 1.
+func New[K comparable, V any](max int) *Cache[K, V] {
+	if max < 1 {
+		max = 1
+	}
+
+	return &Cache[K, V]{
+		max:   max,
+		order: list.New(),
+		items: map[K]*list.Element{},
+	}
+}
+
+// Get returns the value cached under key, if any, marking it most recently
+// used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return e.Value.(*entry[K, V]).value, true
+}
+
+// Add caches value under key, marking it most recently used, and evicts the
+// least recently used entry if the cache is over capacity.
+func (c *Cache[K, V]) Add(key K, value V) {
+	if e, ok := c.items[key]; ok {
+		e.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(e)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key, value})
+
+	if c.order.Len() > c.max {
+		c.remove(c.order.Back())
+	}
+}
+
+// Remove removes the entry cached under key, if any.
+func (c *Cache[K, V]) Remove(key K) {
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+}
+
+// RemoveFunc removes the entries for which remove returns true.
+func (c *Cache[K, V]) RemoveFunc(remove func(key K, value V) bool) {
+	for e := c.order.Front(); e != nil; {
+		next := e.Next()
+
+		if en := e.Value.(*entry[K, V]); remove(en.key, en.value) {
+			c.remove(e)
+		}
+
+		e = next
+	}
+}
+
+// Range calls f with each entry, most recently used first, without marking
+// them used.
+func (c *Cache[K, V]) Range(f func(key K, value V)) {
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		en := e.Value.(*entry[K, V])
+		f(en.key, en.value)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *Cache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) remove(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.items, e.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func keys(c *Cache[string, int]) []string {
+	var keys []string
+	c.Range(func(key string, _ int) {
+		keys = append(keys, key)
+	})
+
+	return keys
+}
+
+func TestCache(t *testing.T) {
+	c := New[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	// Getting a marks it most recently used, such that b is evicted.
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("expected '1'; got '%d', '%t'", v, ok)
+	}
+
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+
+	if expect := []string{"c", "a"}; !reflect.DeepEqual(keys(c), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, keys(c))
+	}
+
+	// Adding an existing key replaces its value without evicting.
+	c.Add("a", 4)
+
+	if v, _ := c.Get("a"); v != 4 || c.Len() != 2 {
+		t.Errorf("expected '4' of '2' entries; got '%d' of '%d'", v, c.Len())
+	}
+
+	c.Remove("a")
+
+	if expect := []string{"c"}; !reflect.DeepEqual(keys(c), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, keys(c))
+	}
+}
+
+func TestCache_RemoveFunc(t *testing.T) {
+	c := New[string, int](4)
+	for i, k := range []string{"a", "b", "c", "d"} {
+		c.Add(k, i)
+	}
+
+	c.RemoveFunc(func(_ string, v int) bool {
+		return v%2 == 0
+	})
+
+	if expect := []string{"d", "b"}; !reflect.DeepEqual(keys(c), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, keys(c))
+	}
+}
+
+func TestNew_minimumSize(t *testing.T) {
+	c := New[string, int](0)
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	if expect := []string{"b"}; !reflect.DeepEqual(keys(c), expect) {
+		t.Errorf("expected '%v'; got '%v'", expect, keys(c))
+	}
+}
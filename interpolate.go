@@ -0,0 +1,146 @@
+package seaweed
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// At returns a Forecast synthesized for the instant t by interpolating
+// between the forecasts it's passed, which must be ordered by Timestamp. It
+// returns false if t falls outside of the forecasts' time range.
+//
+// Heights, periods, wind speeds, temperature and pressure are interpolated
+// linearly; directions are interpolated along the shortest arc, such that
+// 350° and 10° interpolate to 0° rather than 180°. Categorical fields, such as
+// ratings, units, compass directions and Condition.Weather, are carried from
+// the nearest forecast.
+func At(forecasts []Forecast, t time.Time) (Forecast, bool) {
+	ts := FlexInt64(t.Unix())
+	n := len(forecasts)
+	if n == 0 || ts < forecasts[0].Timestamp || ts > forecasts[n-1].Timestamp {
+		return Forecast{}, false
+	}
+
+	// i is the index of the first forecast at or after t.
+	i := sort.Search(n, func(i int) bool {
+		return forecasts[i].Timestamp >= ts
+	})
+
+	if forecasts[i].Timestamp == ts {
+		return forecasts[i], true
+	}
+
+	a, b := forecasts[i-1], forecasts[i]
+	w := float64(ts-a.Timestamp) / float64(b.Timestamp-a.Timestamp)
+
+	return interpolate(a, b, w), true
+}
+
+// Resample returns forecasts spaced step apart, from the first of the
+// forecasts it's passed through the last, synthesized by At. The forecasts
+// must be ordered by Timestamp. Resample returns nil if step is not positive.
+func Resample(forecasts []Forecast, step time.Duration) []Forecast {
+	if step <= 0 || len(forecasts) == 0 {
+		return nil
+	}
+
+	start := time.Unix(int64(forecasts[0].Timestamp), 0)
+	end := time.Unix(int64(forecasts[len(forecasts)-1].Timestamp), 0)
+
+	var resampled []Forecast
+	for t := start; !t.After(end); t = t.Add(step) {
+		f, _ := At(forecasts, t)
+		resampled = append(resampled, f)
+	}
+
+	return resampled
+}
+
+// interpolate returns the forecast the fraction w of the way from a to b.
+func interpolate(a, b Forecast, w float64) Forecast {
+	nearest := a
+	if w >= 0.5 {
+		nearest = b
+	}
+
+	f := nearest
+	f.Timestamp = a.Timestamp + FlexInt64(math.Round(w*float64(b.Timestamp-a.Timestamp)))
+	// LocalTimestamp retains its offset from Timestamp.
+	f.LocalTimestamp = f.Timestamp + (nearest.LocalTimestamp - nearest.Timestamp)
+
+	f.Swell.MinBreakingHeight = lerpInt(a.Swell.MinBreakingHeight, b.Swell.MinBreakingHeight, w)
+	f.Swell.AbsMinBreakingHeight = lerpFloat(a.Swell.AbsMinBreakingHeight, b.Swell.AbsMinBreakingHeight, w)
+	f.Swell.MaxBreakingHeight = lerpInt(a.Swell.MaxBreakingHeight, b.Swell.MaxBreakingHeight, w)
+	f.Swell.AbsMaxBreakingHeight = lerpFloat(a.Swell.AbsMaxBreakingHeight, b.Swell.AbsMaxBreakingHeight, w)
+	f.Swell.Probability = lerpInt(a.Swell.Probability, b.Swell.Probability, w)
+
+	f.Swell.Components.Combined = interpolateComponent(a.Swell.Components.Combined, b.Swell.Components.Combined, w)
+	f.Swell.Components.Primary = interpolateComponent(a.Swell.Components.Primary, b.Swell.Components.Primary, w)
+	f.Swell.Components.Secondary = interpolateOptionalComponent(a.Swell.Components.Secondary, b.Swell.Components.Secondary, nearest.Swell.Components.Secondary, w)
+	f.Swell.Components.Tertiary = interpolateOptionalComponent(a.Swell.Components.Tertiary, b.Swell.Components.Tertiary, nearest.Swell.Components.Tertiary, w)
+
+	f.Wind.Speed = lerpInt(a.Wind.Speed, b.Wind.Speed, w)
+	f.Wind.Direction = FlexInt64(math.Round(lerpDegrees(float64(a.Wind.Direction), float64(b.Wind.Direction), w)))
+	f.Wind.Chill = lerpInt64(a.Wind.Chill, b.Wind.Chill, w)
+	f.Wind.Gusts = lerpInt64(a.Wind.Gusts, b.Wind.Gusts, w)
+
+	f.Condition.Pressure = lerpInt64(a.Condition.Pressure, b.Condition.Pressure, w)
+	f.Condition.Temperature = lerpInt64(a.Condition.Temperature, b.Condition.Temperature, w)
+
+	return f
+}
+
+func interpolateComponent(a, b Component, w float64) Component {
+	c := a
+	if w >= 0.5 {
+		c = b
+	}
+
+	c.Height = lerpFloat(a.Height, b.Height, w)
+	c.Period = lerpInt(a.Period, b.Period, w)
+	c.Direction = FlexFloat(lerpDegrees(float64(a.Direction), float64(b.Direction), w))
+
+	return c
+}
+
+// interpolateOptionalComponent interpolates between a and b if both are
+// present, and otherwise carries nearest.
+func interpolateOptionalComponent(a, b, nearest *Component, w float64) *Component {
+	if a == nil || b == nil {
+		if nearest == nil {
+			return nil
+		}
+
+		c := *nearest
+		return &c
+	}
+
+	c := interpolateComponent(*a, *b, w)
+
+	return &c
+}
+
+func lerp(a, b, w float64) float64 {
+	return a + w*(b-a)
+}
+
+func lerpFloat(a, b FlexFloat, w float64) FlexFloat {
+	return FlexFloat(lerp(float64(a), float64(b), w))
+}
+
+func lerpInt(a, b FlexInt, w float64) FlexInt {
+	return FlexInt(math.Round(lerp(float64(a), float64(b), w)))
+}
+
+func lerpInt64(a, b FlexInt64, w float64) FlexInt64 {
+	return FlexInt64(math.Round(lerp(float64(a), float64(b), w)))
+}
+
+// lerpDegrees interpolates between the directions a and b, in degrees, along
+// the shortest arc between them.
+func lerpDegrees(a, b, w float64) float64 {
+	delta := Direction(a).Difference(Direction(b))
+
+	return normalizeDegrees(a + w*delta)
+}
@@ -0,0 +1,140 @@
+package seaweed
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func interpolationFixture() []Forecast {
+	return []Forecast{{
+		Timestamp:      1677931200,
+		LocalTimestamp: 1677913200,
+		SolidRating:    1,
+		Swell: Swell{
+			MinBreakingHeight:    2,
+			AbsMinBreakingHeight: 2,
+			MaxBreakingHeight:    3,
+			AbsMaxBreakingHeight: 3,
+			Unit:                 "ft",
+			Components: Components{
+				Primary:   Component{Height: 3, Period: 8, Direction: 350, CompassDirection: "S"},
+				Secondary: &Component{Height: 1, Period: 6, Direction: 90},
+			},
+		},
+		Wind:      Wind{Speed: 10, Direction: 300, Gusts: 15},
+		Condition: Condition{Pressure: 1000, Temperature: 50, Weather: "10"},
+	}, {
+		Timestamp:      1677942000,
+		LocalTimestamp: 1677924000,
+		SolidRating:    3,
+		Swell: Swell{
+			MinBreakingHeight:    5,
+			AbsMinBreakingHeight: 5,
+			MaxBreakingHeight:    6,
+			AbsMaxBreakingHeight: 6,
+			Unit:                 "ft",
+			Components: Components{
+				Primary: Component{Height: 6, Period: 14, Direction: 20, CompassDirection: "SSW"},
+			},
+		},
+		Wind:      Wind{Speed: 4, Direction: 30, Gusts: 6},
+		Condition: Condition{Pressure: 1006, Temperature: 56, Weather: "22"},
+	}}
+}
+
+func TestAt(t *testing.T) {
+	forecasts := interpolationFixture()
+
+	if _, ok := At(forecasts, time.Unix(1677931199, 0)); ok {
+		t.Error("expected At not to extrapolate before the first forecast")
+	}
+
+	if _, ok := At(forecasts, time.Unix(1677942001, 0)); ok {
+		t.Error("expected At not to extrapolate after the last forecast")
+	}
+
+	if _, ok := At(nil, time.Unix(1677942000, 0)); ok {
+		t.Error("expected At to return false for no forecasts")
+	}
+
+	if f, ok := At(forecasts, time.Unix(1677942000, 0)); !ok || f.Condition.Weather != "22" || f.Wind.Speed != 4 {
+		t.Errorf("expected At to return an exact timestep as is; got '%+v'", f)
+	}
+
+	// One hour in: a third of the way from the first forecast to the second.
+	f, ok := At(forecasts, time.Unix(1677934800, 0))
+	if !ok {
+		t.Fatal("expected At to interpolate within the forecasts' range")
+	}
+
+	if f.Timestamp != 1677934800 || f.LocalTimestamp != 1677916800 {
+		t.Errorf("expected timestamps '1677934800' and '1677916800'; got '%d' and '%d'", f.Timestamp, f.LocalTimestamp)
+	}
+
+	if f.Swell.MinBreakingHeight != 3 || f.Swell.AbsMaxBreakingHeight != 4 {
+		t.Errorf("expected breaking heights '3' and '4'; got '%d' and '%v'", f.Swell.MinBreakingHeight, f.Swell.AbsMaxBreakingHeight)
+	}
+
+	primary := f.Swell.Components.Primary
+	if primary.Height != 4 || primary.Period != 10 || math.Abs(float64(primary.Direction)-0) > 1e-9 {
+		t.Errorf("expected primary 4ft @ 10s from 0 degrees; got '%+v'", primary)
+	}
+
+	if primary.CompassDirection != "S" || f.SolidRating != 1 || f.Condition.Weather != "10" {
+		t.Errorf("expected categorical fields from the nearest forecast; got '%s', '%d', '%s'", primary.CompassDirection, f.SolidRating, f.Condition.Weather)
+	}
+
+	if f.Swell.Components.Secondary == nil || f.Swell.Components.Secondary.Height != 1 {
+		t.Errorf("expected the secondary component to be carried from the nearest forecast; got '%v'", f.Swell.Components.Secondary)
+	}
+
+	if f.Wind.Speed != 8 || f.Wind.Gusts != 12 || f.Wind.Direction != 330 {
+		t.Errorf("expected wind 8 gusting 12 from 330; got '%d' gusting '%d' from '%d'", f.Wind.Speed, f.Wind.Gusts, f.Wind.Direction)
+	}
+
+	if f.Condition.Pressure != 1002 || f.Condition.Temperature != 52 {
+		t.Errorf("expected pressure '1002' and temperature '52'; got '%d' and '%d'", f.Condition.Pressure, f.Condition.Temperature)
+	}
+}
+
+func TestResample(t *testing.T) {
+	forecasts := interpolationFixture()
+	hourly := Resample(forecasts, time.Hour)
+
+	if len(hourly) != 4 {
+		t.Fatalf("expected '4' hourly forecasts; got '%d'", len(hourly))
+	}
+
+	for i, f := range hourly {
+		if expected := int64(1677931200 + i*3600); int64(f.Timestamp) != expected {
+			t.Errorf("expected forecast %d Timestamp '%d'; got '%d'", i, expected, f.Timestamp)
+		}
+	}
+
+	if hourly[2].Swell.Components.Secondary != nil {
+		t.Error("expected the secondary component to be absent nearest the second forecast")
+	}
+
+	if Resample(forecasts, 0) != nil {
+		t.Error("expected Resample to return nil for a non-positive step")
+	}
+}
+
+func TestLerpDegrees(t *testing.T) {
+	tests := []struct {
+		a, b, w, expect float64
+	}{
+		{350, 10, 0.5, 0},
+		{10, 350, 0.5, 0},
+		{90, 240, 0.25, 127.5},
+		{0, 90, 1, 90},
+		{-10, 370, 0.5, 0},
+	}
+
+	for _, test := range tests {
+		if got := lerpDegrees(test.a, test.b, test.w); math.Abs(got-test.expect) > 1e-9 {
+			t.Errorf("expected lerpDegrees(%v, %v, %v) to be '%v'; got '%v'", test.a, test.b, test.w, test.expect, got)
+		}
+	}
+}
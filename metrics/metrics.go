@@ -0,0 +1,360 @@
+// Package metrics provides a minimal, standard library-only implementation of
+// counters, gauges, and histograms exposed in the Prometheus text exposition
+// format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default histogram bucket upper bounds, in seconds,
+// suited to measuring HTTP request latency.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds a set of metric families and writes them in the Prometheus
+// text exposition format.
+type Registry struct {
+	mu        sync.Mutex
+	families  []family
+	onCollect []func()
+}
+
+// family is a named metric family with a set of labeled series.
+type family interface {
+	name() string
+	write(w io.Writer) error
+}
+
+// NewRegistry returns an empty *Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// OnCollect registers f to be called before each exposition, such that gauges
+// reflecting current state may be refreshed lazily.
+func (r *Registry) OnCollect(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onCollect = append(r.onCollect, f)
+}
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.families {
+		if existing.name() == f.name() {
+			panic(fmt.Sprintf("metrics: duplicate metric %q", f.name()))
+		}
+	}
+
+	r.families = append(r.families, f)
+	sort.Slice(r.families, func(i, j int) bool {
+		return r.families[i].name() < r.families[j].name()
+	})
+}
+
+// Write writes each of the registry's metric families to w in the Prometheus
+// text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	onCollect := append([]func(){}, r.onCollect...)
+	families := append([]family{}, r.families...)
+	r.mu.Unlock()
+
+	for _, f := range onCollect {
+		f()
+	}
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP implements http.Handler, serving the registry's metrics.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.Write(w)
+}
+
+// vec holds the labeled series of a metric family.
+type vec struct {
+	mu         sync.Mutex
+	metricName string
+	help       string
+	typ        string
+	labelNames []string
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// buckets and sum are only used by histograms.
+	buckets []uint64
+	sum     float64
+}
+
+func newVec(name, help, typ string, labelNames []string) vec {
+	return vec{
+		metricName: name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		series:     map[string]*series{},
+	}
+}
+
+func (v *vec) name() string {
+	return v.metricName
+}
+
+// get returns the series with the given label values, creating it if
+// necessary. v.mu must be held.
+func (v *vec) get(labelValues []string) *series {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values; got %d", v.metricName, len(v.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string{}, labelValues...)}
+		v.series[key] = s
+	}
+
+	return s
+}
+
+// sorted returns the vec's series ordered by label values. v.mu must be held.
+func (v *vec) sorted() []*series {
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]*series, len(keys))
+	for i, k := range keys {
+		sorted[i] = v.series[k]
+	}
+
+	return sorted
+}
+
+func (v *vec) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.metricName, helpEscaper.Replace(v.help), v.metricName, v.typ)
+
+	return err
+}
+
+// writeSimple writes a counter or gauge family.
+func (v *vec) writeSimple(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+
+	for _, s := range v.sorted() {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.metricName, formatLabels(v.labelNames, s.labelValues, "", ""), formatValue(s.value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CounterVec is a family of monotonically increasing counters partitioned by
+// label values.
+type CounterVec struct {
+	vec
+}
+
+// NewCounterVec registers and returns a *CounterVec.
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{newVec(name, help, "counter", labelNames)}
+	r.register(c)
+
+	return c
+}
+
+// Inc increments the counter with the given label values by 1.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter with the given label values by delta, which must
+// not be negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.get(labelValues).value += delta
+}
+
+// Value returns the value of the counter with the given label values.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(labelValues).value
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	return c.writeSimple(w)
+}
+
+// GaugeVec is a family of gauges partitioned by label values.
+type GaugeVec struct {
+	vec
+}
+
+// NewGaugeVec registers and returns a *GaugeVec.
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{newVec(name, help, "gauge", labelNames)}
+	r.register(g)
+
+	return g
+}
+
+// Set sets the gauge with the given label values to value.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.get(labelValues).value = value
+}
+
+// Reset removes all of the gauge's series.
+func (g *GaugeVec) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.series = map[string]*series{}
+}
+
+func (g *GaugeVec) write(w io.Writer) error {
+	return g.writeSimple(w)
+}
+
+// HistogramVec is a family of histograms partitioned by label values.
+type HistogramVec struct {
+	vec
+	bounds []float64
+}
+
+// NewHistogramVec registers and returns a *HistogramVec with the given
+// ascending bucket upper bounds. DefaultBuckets is used if bounds is nil.
+func (r *Registry) NewHistogramVec(name, help string, bounds []float64, labelNames ...string) *HistogramVec {
+	if bounds == nil {
+		bounds = DefaultBuckets
+	}
+
+	h := &HistogramVec{newVec(name, help, "histogram", labelNames), append([]float64{}, bounds...)}
+	r.register(h)
+
+	return h
+}
+
+// Observe adds value to the histogram with the given label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.get(labelValues)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.bounds))
+	}
+
+	for i, bound := range h.bounds {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+
+	s.value++
+	s.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.writeHeader(w); err != nil {
+		return err
+	}
+
+	for _, s := range h.sorted() {
+		for i, bound := range h.bounds {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, formatLabels(h.labelNames, s.labelValues, "le", formatValue(bound)), s.buckets[i]); err != nil {
+				return err
+			}
+		}
+
+		labels := formatLabels(h.labelNames, s.labelValues, "", "")
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %s\n%s_sum%s %s\n%s_count%s %s\n",
+			h.metricName, formatLabels(h.labelNames, s.labelValues, "le", "+Inf"), formatValue(s.value),
+			h.metricName, labels, formatValue(s.sum),
+			h.metricName, labels, formatValue(s.value),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatLabels formats label names and values as {name="value",...}, with an
+// optional extra label appended.
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(values[i])+`"`)
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+labelEscaper.Replace(extraValue)+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
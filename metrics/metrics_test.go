@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.NewCounterVec("test_requests_total", "Total requests.", "code")
+	requests.Inc("200")
+	requests.Inc("200")
+	requests.Add(0.5, `"weird"\value`)
+
+	heights := r.NewGaugeVec("test_height", "Height\nin feet.", "spot")
+	r.OnCollect(func() {
+		heights.Reset()
+		heights.Set(4.5, "391")
+	})
+	heights.Set(1, "stale")
+
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP test_height Height\nin feet.
+# TYPE test_height gauge
+test_height{spot="391"} 4.5
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 5.55
+test_latency_seconds_count 3
+# HELP test_requests_total Total requests.
+# TYPE test_requests_total counter
+test_requests_total{code="\"weird\"\\value"} 0.5
+test_requests_total{code="200"} 2
+`
+
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	if v := requests.Value("200"); v != 2 {
+		t.Errorf("expected counter value '2'; got '%v'", v)
+	}
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Total.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("expected text exposition content type; got '%s'", ct)
+	}
+
+	if rec.Body.String() != "# HELP test_total Total.\n# TYPE test_total counter\ntest_total 1\n" {
+		t.Errorf("unexpected body '%s'", rec.Body.String())
+	}
+}
+
+func TestRegistry_duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a duplicate metric to panic")
+		}
+	}()
+
+	r := NewRegistry()
+	r.NewGaugeVec("test", "Test.")
+	r.NewCounterVec("test", "Test.")
+}
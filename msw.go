@@ -0,0 +1,215 @@
+package seaweed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mdb/seaweed/internal/lru"
+	"github.com/sirupsen/logrus"
+)
+
+// magicSeaweed is the Provider fetching forecasts from the Magic Seaweed API.
+// It is configured by the Client options WithBaseURL, WithHTTPClient,
+// WithLogger, WithUnits, WithStrictDecoding and WithMaxResponseBytes, and is
+// the Provider a Client uses unless it is configured WithProvider.
+type magicSeaweed struct {
+	// client is the *Client whose configuration the provider uses.
+	client *Client
+	// mu guards validators.
+	mu sync.Mutex
+	// validators holds the validators of the last successful response for
+	// each spot ID, such that the client's cached forecasts may be
+	// revalidated. It's created on first use, once the client is configured.
+	validators *lru.Cache[string, validators]
+}
+
+// validators are the validators of a spot's last successful forecast
+// response.
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+// set sets the conditional request headers with which the API may revalidate
+// the response.
+func (v validators) set(h http.Header) {
+	if v.etag != "" {
+		h.Set("If-None-Match", v.etag)
+	}
+
+	if v.lastModified != "" {
+		h.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+func newMagicSeaweed(c *Client) *magicSeaweed {
+	return &magicSeaweed{client: c}
+}
+
+// Forecast implements Provider.
+func (m *magicSeaweed) Forecast(spotID string) ([]Forecast, error) {
+	c := m.client
+	url := fmt.Sprintf("%s/api/%s/forecast/?spot_id=%s", c.baseURL, c.apiKey, spotID)
+	if c.units != "" {
+		url = fmt.Sprintf("%s&units=%s", url, c.units)
+	}
+	forecasts := []Forecast{}
+
+	// Revalidate the client's cached forecasts, if any.
+	header := http.Header{}
+	cached, ok := c.cached(spotID)
+	if ok {
+		m.mu.Lock()
+		if v, found := m.cache().Get(spotID); found {
+			v.set(header)
+		}
+		m.mu.Unlock()
+	}
+
+	resp, err := m.get(url, header, func(body io.Reader) error {
+		var err error
+		forecasts, err = decodeForecasts(body, c.strict)
+
+		return err
+	})
+	if err != nil {
+		return []Forecast{}, err
+	}
+
+	m.store(spotID, resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified {
+		return cached, nil
+	}
+
+	return forecasts, nil
+}
+
+// cache returns the validators cache, creating it if need be. m.mu must be
+// held.
+func (m *magicSeaweed) cache() *lru.Cache[string, validators] {
+	if m.validators == nil {
+		m.validators = lru.New[string, validators](m.client.maxCachedSpots)
+	}
+
+	return m.validators
+}
+
+// store retains the validators from a spot's successful response header h.
+func (m *magicSeaweed) store(spot string, h http.Header) {
+	v := validators{
+		etag:         h.Get("ETag"),
+		lastModified: h.Get("Last-Modified"),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if prev, ok := m.cache().Get(spot); ok {
+		// A 304 response need not repeat the validators.
+		if v.etag == "" {
+			v.etag = prev.etag
+		}
+
+		if v.lastModified == "" {
+			v.lastModified = prev.lastModified
+		}
+	}
+
+	m.cache().Add(spot, v)
+}
+
+// get performs a GET request against url with the header it's passed and, if
+// the API responds with an HTTP status code of 200, streams the size-bounded
+// response body to decode. An HTTP status code of 304 is not an error if the
+// header holds validators; the response body is not decoded in such
+// instances. A 304 to an unconditional request is an *HTTPError, as there is
+// no cached response it may refer to.
+func (m *magicSeaweed) get(url string, header http.Header, decode func(io.Reader) error) (*http.Response, error) {
+	c := m.client
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	sanitizedURL := strings.Replace(url, c.apiKey, "<REDACTED>", 1)
+	sanitizedURL = strings.Replace(sanitizedURL, c.baseURL, "", 1)
+
+	// Retain an excerpt of the body for use in error messages; retain the
+	// full body when debug logging is enabled.
+	excerpt := &excerptWriter{max: maxExcerptBytes}
+	if c.Logger.IsLevelEnabled(logrus.DebugLevel) {
+		excerpt.max = c.maxResponseBytes
+	}
+	// Allocate the excerpt once, rather than growing it as the body is read.
+	excerpt.buf.Grow(int(maxExcerptBytes))
+
+	body := io.TeeReader(&limitedReader{r: resp.Body, n: c.maxResponseBytes}, excerpt)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && (header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""):
+	case resp.StatusCode != http.StatusOK:
+		err = &HTTPError{URL: sanitizedURL, StatusCode: resp.StatusCode}
+		_, _ = io.Copy(io.Discard, body)
+	default:
+		if err = decode(body); err != nil {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				err = fmt.Errorf("unexpected API response '%s': %w", excerpt, err)
+			}
+		}
+	}
+
+	l := c.Logger.WithFields(
+		logrus.Fields{
+			"url":         sanitizedURL,
+			"http_status": resp.StatusCode,
+			"body":        excerpt.String(),
+		})
+
+	l.Debugf("Magic Seaweed API response")
+
+	return resp, err
+}
+
+// excerptWriter is an io.Writer retaining at most max of the bytes written to it.
+type excerptWriter struct {
+	buf       bytes.Buffer
+	max       int64
+	truncated bool
+}
+
+func (e *excerptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := e.max - int64(e.buf.Len()); int64(len(p)) > room {
+		if room < 0 {
+			room = 0
+		}
+		p = p[:room]
+		e.truncated = true
+	}
+	e.buf.Write(p)
+
+	return n, nil
+}
+
+func (e *excerptWriter) String() string {
+	if e.truncated {
+		return e.buf.String() + "..."
+	}
+
+	return e.buf.String()
+}
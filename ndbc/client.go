@@ -0,0 +1,98 @@
+package ndbc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the NDBC realtime2 data directory.
+const DefaultBaseURL = "https://www.ndbc.noaa.gov/data/realtime2"
+
+// maxResponseBytes is the maximum number of response body bytes read from
+// NDBC; realtime2 files hold 45 days of observations.
+const maxResponseBytes = 10 << 20
+
+// Format is an NDBC realtime2 file format.
+type Format string
+
+const (
+	// Standard is the standard meteorological data format (.txt).
+	Standard Format = "txt"
+	// Spectral is the spectral wave summary data format (.spec).
+	Spectral Format = "spec"
+)
+
+// Client fetches NDBC realtime2 observations.
+type Client struct {
+	// baseURL is the realtime2 data directory URL.
+	baseURL string
+	// httpClient is a *http.Client.
+	httpClient *http.Client
+}
+
+// ClientOption configures one or more Client fields.
+type ClientOption = func(c *Client)
+
+// WithBaseURL is a ClientOption to configure a *Client's baseURL.
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithHTTPClient is a ClientOption to configure a *Client's httpClient, such
+// as to share the *http.Client configured on a *seaweed.Client via
+// seaweed.WithHTTPClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient returns a *Client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Observations fetches and parses a station's realtime observations in the
+// given format, ordered by time. Station IDs are case insensitive, such as
+// "44009" or "ACYN4".
+func (c *Client) Observations(station string, format Format) ([]Observation, error) {
+	if station == "" {
+		return nil, fmt.Errorf("empty station ID")
+	}
+
+	u := fmt.Sprintf("%s/%s.%s", strings.TrimSuffix(c.baseURL, "/"), url.PathEscape(strings.ToUpper(station)), format)
+
+	resp, err := c.httpClient.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+		return nil, fmt.Errorf("GET %s returned HTTP status code %d", u, resp.StatusCode)
+	}
+
+	observations, err := Parse(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", u, err)
+	}
+
+	return observations, nil
+}
@@ -0,0 +1,86 @@
+package ndbc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testServerAndClient(t *testing.T) *Client {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(server.Close)
+
+	return NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+}
+
+func TestObservations(t *testing.T) {
+	tests := []struct {
+		desc        string
+		station     string
+		format      Format
+		expectCount int
+		expectError string
+	}{{
+		desc:        "standard",
+		station:     "44009",
+		format:      Standard,
+		expectCount: 5,
+	}, {
+		desc:        "spectral",
+		station:     "44009",
+		format:      Spectral,
+		expectCount: 3,
+	}, {
+		desc:        "unknown station",
+		station:     "99999",
+		format:      Standard,
+		expectError: "/99999.txt returned HTTP status code 404",
+	}, {
+		desc:        "empty station",
+		format:      Standard,
+		expectError: "empty station ID",
+	}}
+
+	c := testServerAndClient(t)
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			observations, err := c.Observations(test.station, test.format)
+			if test.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectError) {
+					t.Errorf("expected error containing '%s'; got '%v'", test.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected Observations not to error; got '%v'", err)
+			}
+
+			if len(observations) != test.expectCount {
+				t.Errorf("expected '%d' observations; got '%d'", test.expectCount, len(observations))
+			}
+		})
+	}
+}
+
+func TestObservations_invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "44009.txt"), []byte("<html>Not Found</html>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewTLSServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL+"/"), WithHTTPClient(server.Client()))
+	if _, err := c.Observations("44009", Standard); err == nil || !strings.Contains(err.Error(), "data precedes header") {
+		t.Errorf("expected a parse error; got '%v'", err)
+	}
+}
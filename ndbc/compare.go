@@ -0,0 +1,258 @@
+package ndbc
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/verify"
+)
+
+// DefaultTolerance is the default maximum time between a forecast and the
+// observation aligned with it.
+const DefaultTolerance = 30 * time.Minute
+
+// Comparison is a forecast and the observation nearest its time.
+type Comparison struct {
+	// Forecast is the forecast.
+	Forecast seaweed.Forecast
+	// Observation is the observation nearest the forecast's time, converted
+	// to the forecast's units; see Observation.Convert.
+	Observation Observation
+	// Offset is the observation's time less the forecast's.
+	Offset time.Duration
+}
+
+// WaveHeightError returns the forecast's combined swell height less the
+// observed significant wave height, and false if the latter is missing.
+func (c Comparison) WaveHeightError() (float64, bool) {
+	if !c.Observation.WaveHeight.Valid() {
+		return 0, false
+	}
+
+	return float64(c.Forecast.Swell.Components.Combined.Height) - float64(c.Observation.WaveHeight), true
+}
+
+// WindSpeedError returns the forecast's wind speed less the observed wind
+// speed, and false if the latter is missing.
+func (c Comparison) WindSpeedError() (float64, bool) {
+	if !c.Observation.WindSpeed.Valid() {
+		return 0, false
+	}
+
+	return float64(c.Forecast.Wind.Speed) - float64(c.Observation.WindSpeed), true
+}
+
+// compareOptions configures Compare.
+type compareOptions struct {
+	tolerance time.Duration
+}
+
+// CompareOption configures Compare.
+type CompareOption = func(o *compareOptions)
+
+// WithTolerance is a CompareOption configuring the maximum time between a
+// forecast and the observation aligned with it. It defaults to
+// DefaultTolerance.
+func WithTolerance(d time.Duration) CompareOption {
+	return func(o *compareOptions) {
+		o.tolerance = d
+	}
+}
+
+// Compare aligns each forecast with the observation nearest its Timestamp,
+// converted to the forecast's units. Forecasts without an observation within
+// the tolerance are omitted. The observations are expected to be ordered by
+// time, as Parse returns them.
+func Compare(forecasts []seaweed.Forecast, observations []Observation, opts ...CompareOption) []Comparison {
+	o := &compareOptions{tolerance: DefaultTolerance}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var comparisons []Comparison
+	for _, f := range forecasts {
+		t := time.Unix(int64(f.Timestamp), 0)
+
+		obs, ok := nearest(observations, t, o.tolerance)
+		if !ok {
+			continue
+		}
+
+		comparisons = append(comparisons, Comparison{
+			Forecast:    f,
+			Observation: obs.Convert(f.Swell.Unit, f.Wind.Unit, f.Condition.Unit),
+			Offset:      obs.Time.Sub(t),
+		})
+	}
+
+	return comparisons
+}
+
+// nearest returns the observation nearest t, and false if there is none
+// within tolerance.
+func nearest(observations []Observation, t time.Time, tolerance time.Duration) (Observation, bool) {
+	i := sort.Search(len(observations), func(i int) bool {
+		return !observations[i].Time.Before(t)
+	})
+
+	best, found := Observation{}, false
+	bestOffset := tolerance
+
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(observations) {
+			continue
+		}
+
+		offset := observations[j].Time.Sub(t)
+		if offset < 0 {
+			offset = -offset
+		}
+
+		if offset <= bestOffset {
+			best, found, bestOffset = observations[j], true, offset
+		}
+	}
+
+	return best, found
+}
+
+const (
+	feetPerMeter   = 3.28084
+	mphPerMps      = 2.236936
+	kphPerMps      = 3.6
+	knotsPerMps    = 1.943844
+	fahrenheitBase = 32
+)
+
+// Convert returns the observation with its heights, speeds and temperatures
+// converted from NDBC's units to the Magic Seaweed units it's passed: a
+// height unit of "ft" or "m", a speed unit of "mph", "kph" or "kts", and a
+// temperature unit of "f" or "c". Unrecognized units are left unconverted.
+func (o Observation) Convert(heightUnit, speedUnit, temperatureUnit string) Observation {
+	if heightUnit == "ft" {
+		for _, v := range []*Value{&o.WaveHeight, &o.SwellHeight, &o.WindWaveHeight} {
+			*v *= feetPerMeter
+		}
+	}
+
+	var speed float64
+	switch speedUnit {
+	case "mph":
+		speed = mphPerMps
+	case "kph":
+		speed = kphPerMps
+	case "kts":
+		speed = knotsPerMps
+	}
+
+	if speed != 0 {
+		o.WindSpeed *= Value(speed)
+		o.WindGust *= Value(speed)
+	}
+
+	if temperatureUnit == "f" {
+		for _, v := range []*Value{&o.AirTemperature, &o.WaterTemperature} {
+			*v = *v*9/5 + fahrenheitBase
+		}
+	}
+
+	return o
+}
+
+// Forecast returns the observation as a seaweed.Forecast, such that forecasts
+// may be verified against it, alongside the verify.Variables it reports: its
+// significant wave height, dominant period and mean wave direction as the
+// combined swell component, alongside its wind and conditions, in the Magic
+// Seaweed units it's passed (see Convert). Directions are converted to the
+// Magic Seaweed convention of the direction travelled toward, alongside the
+// compass point they come from.
+//
+// Measurements the observation lacks are left unset and absent from the
+// returned Variables; a missing direction leaves its CompassDirection empty.
+// Observations report no star ratings, and the returned Forecast has no
+// IssueTimestamp.
+func (o Observation) Forecast(heightUnit, speedUnit, temperatureUnit string) (seaweed.Forecast, verify.Variables) {
+	c := o.Convert(heightUnit, speedUnit, temperatureUnit)
+
+	f := seaweed.Forecast{
+		Timestamp:      seaweed.FlexInt64(c.Time.Unix()),
+		LocalTimestamp: seaweed.FlexInt64(c.Time.Unix()),
+	}
+
+	var vars verify.Variables
+
+	f.Swell.Unit = heightUnit
+	combined := &f.Swell.Components.Combined
+	if c.WaveHeight.Valid() {
+		combined.Height = seaweed.FlexFloat(c.WaveHeight)
+		vars |= verify.Height
+	}
+
+	if c.DominantPeriod.Valid() {
+		combined.Period = seaweed.FlexInt(math.Round(float64(c.DominantPeriod)))
+		vars |= verify.Period
+	}
+
+	if c.MeanWaveDirection.Valid() {
+		from := seaweed.Direction(c.MeanWaveDirection)
+		combined.Direction = seaweed.FlexFloat(from.Opposite())
+		combined.CompassDirection = from.Compass()
+	}
+	f.Swell.Components.Primary = *combined
+
+	f.Wind.Unit = speedUnit
+	if c.WindSpeed.Valid() {
+		f.Wind.Speed = seaweed.FlexInt(math.Round(float64(c.WindSpeed)))
+		vars |= verify.WindSpeed
+	}
+
+	if c.WindDirection.Valid() {
+		from := seaweed.Direction(c.WindDirection)
+		f.Wind.Direction = seaweed.FlexInt64(math.Round(float64(from.Opposite())))
+		f.Wind.CompassDirection = from.Compass()
+	}
+
+	if c.WindGust.Valid() {
+		f.Wind.Gusts = seaweed.FlexInt64(math.Round(float64(c.WindGust)))
+	}
+
+	f.Condition.Unit = temperatureUnit
+	f.Condition.UnitPressure = "mb"
+	if c.Pressure.Valid() {
+		f.Condition.Pressure = seaweed.FlexInt64(math.Round(float64(c.Pressure)))
+	}
+
+	if c.AirTemperature.Valid() {
+		f.Condition.Temperature = seaweed.FlexInt64(math.Round(float64(c.AirTemperature)))
+	}
+
+	return f, vars
+}
+
+// Truth returns a verify.Truth reporting the observation nearest each time,
+// within tolerance, as a seaweed.Forecast in the Magic Seaweed units it's
+// passed, alongside the variables it reports; see Observation.Forecast.
+// Forecasts are therefore verified only against measured variables, and never
+// against star ratings. Observations lacking a wave height are ignored. The
+// observations are expected to be ordered by time.
+func Truth(observations []Observation, tolerance time.Duration, heightUnit, speedUnit, temperatureUnit string) verify.Truth {
+	var valid []Observation
+	for _, o := range observations {
+		if o.WaveHeight.Valid() {
+			valid = append(valid, o)
+		}
+	}
+
+	return verify.TruthFunc(func(t time.Time) (seaweed.Forecast, verify.Variables, bool) {
+		o, ok := nearest(valid, t, tolerance)
+		if !ok {
+			return seaweed.Forecast{}, 0, false
+		}
+
+		f, vars := o.Forecast(heightUnit, speedUnit, temperatureUnit)
+
+		return f, vars, true
+	})
+}
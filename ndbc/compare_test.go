@@ -0,0 +1,192 @@
+package ndbc
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/verify"
+)
+
+func forecastAt(t time.Time, height seaweed.FlexFloat, speed seaweed.FlexInt) seaweed.Forecast {
+	f := seaweed.Forecast{
+		Timestamp:      seaweed.FlexInt64(t.Unix()),
+		IssueTimestamp: seaweed.FlexInt64(t.Add(-24 * time.Hour).Unix()),
+		Swell:          seaweed.Swell{Unit: "ft"},
+		Wind:           seaweed.Wind{Speed: speed, Unit: "mph"},
+		Condition:      seaweed.Condition{Unit: "f"},
+	}
+	f.Swell.Components.Combined.Height = height
+
+	return f
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestCompare(t *testing.T) {
+	observations := parseFixture(t, "44009.txt")
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	forecasts := []seaweed.Forecast{
+		forecastAt(day, 5, 20),
+		forecastAt(day.Add(3*time.Hour+20*time.Minute), 4, 15),
+		forecastAt(day.Add(4*time.Hour+30*time.Minute), 4, 15),
+		forecastAt(day.Add(6*time.Hour+30*time.Minute), 4, 10),
+	}
+
+	comparisons := Compare(forecasts, observations)
+
+	// The 04:30 forecast has no observation within 30 minutes.
+	if len(comparisons) != 3 {
+		t.Fatalf("expected '3' comparisons; got '%d'", len(comparisons))
+	}
+
+	tests := []struct {
+		desc            string
+		c               Comparison
+		expectOffset    time.Duration
+		expectHeightErr float64
+		expectHeightOK  bool
+		expectWindErr   float64
+	}{{
+		desc:            "exact",
+		c:               comparisons[0],
+		expectHeightErr: 5 - 1.6*feetPerMeter,
+		expectHeightOK:  true,
+		expectWindErr:   20 - 8*mphPerMps,
+	}, {
+		desc:            "earlier observation",
+		c:               comparisons[1],
+		expectOffset:    -20 * time.Minute,
+		expectHeightErr: 4 - 1.4*feetPerMeter,
+		expectHeightOK:  true,
+		expectWindErr:   15 - 7*mphPerMps,
+	}, {
+		desc:          "later observation without waves",
+		c:             comparisons[2],
+		expectOffset:  20 * time.Minute,
+		expectWindErr: 10 - 5*mphPerMps,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.c.Offset != test.expectOffset {
+				t.Errorf("expected offset '%s'; got '%s'", test.expectOffset, test.c.Offset)
+			}
+
+			heightErr, ok := test.c.WaveHeightError()
+			if ok != test.expectHeightOK || !approx(heightErr, test.expectHeightErr) {
+				t.Errorf("expected height error '%g' (%t); got '%g' (%t)", test.expectHeightErr, test.expectHeightOK, heightErr, ok)
+			}
+
+			windErr, ok := test.c.WindSpeedError()
+			if !ok || !approx(windErr, test.expectWindErr) {
+				t.Errorf("expected wind speed error '%g'; got '%g' (%t)", test.expectWindErr, windErr, ok)
+			}
+		})
+	}
+
+	if c := Compare(forecasts[2:3], observations, WithTolerance(2*time.Hour)); len(c) != 1 {
+		t.Errorf("expected a wider tolerance to align the 04:30 forecast; got '%d' comparisons", len(c))
+	}
+}
+
+func TestConvert(t *testing.T) {
+	o := newObservation(time.Time{})
+	o.WaveHeight = 2
+	o.WindSpeed = 10
+	o.WindGust = 20
+	o.WaterTemperature = 20
+
+	tests := []struct {
+		desc        string
+		converted   Observation
+		expectWave  float64
+		expectSpeed float64
+		expectGust  float64
+		expectWater float64
+	}{
+		{"us", o.Convert("ft", "mph", "f"), 6.56, 22.37, 44.74, 68},
+		{"eu", o.Convert("m", "kph", "c"), 2, 36, 72, 20},
+		{"knots", o.Convert("m", "kts", "c"), 2, 19.44, 38.88, 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			c := test.converted
+			if !approx(float64(c.WaveHeight), test.expectWave) || !approx(float64(c.WindSpeed), test.expectSpeed) ||
+				!approx(float64(c.WindGust), test.expectGust) || !approx(float64(c.WaterTemperature), test.expectWater) {
+				t.Errorf("expected '%g/%g/%g/%g'; got '%g/%g/%g/%g'", test.expectWave, test.expectSpeed, test.expectGust, test.expectWater,
+					c.WaveHeight, c.WindSpeed, c.WindGust, c.WaterTemperature)
+			}
+
+			if c.SwellHeight.Valid() {
+				t.Errorf("expected a missing value to remain missing; got '%g'", c.SwellHeight)
+			}
+		})
+	}
+}
+
+func TestTruth(t *testing.T) {
+	observations := parseFixture(t, "44009.txt")
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	truth := Truth(observations, DefaultTolerance, "ft", "mph", "f")
+
+	f, vars, ok := truth.At(day.Add(6*time.Hour + 20*time.Minute))
+	if !ok {
+		t.Fatal("expected an observation near 06:20")
+	}
+
+	if vars != verify.Height|verify.Period|verify.WindSpeed {
+		t.Errorf("expected the observed height, period and wind speed; got '%b'", vars)
+	}
+
+	// The 06:50 observation lacks waves; the 06:00 observation is used.
+	if int64(f.Timestamp) != day.Add(6*time.Hour).Unix() {
+		t.Errorf("expected the 06:00 observation; got '%s'", time.Unix(int64(f.Timestamp), 0).UTC())
+	}
+
+	c := f.Swell.Components.Combined
+	if !approx(float64(c.Height), 1.3*feetPerMeter) || c.Period != 10 || c.Direction != 298 || c.CompassDirection != "ESE" {
+		t.Errorf("expected the observed waves, travelling toward 298°; got '%+v'", c)
+	}
+
+	if f.Wind.Speed != 13 || f.Wind.Direction != 150 || f.Wind.Unit != "mph" {
+		t.Errorf("expected the observed wind, travelling toward 150°; got '%+v'", f.Wind)
+	}
+
+	if _, _, ok := truth.At(day.Add(-time.Hour)); ok {
+		t.Error("expected no observation an hour before the first")
+	}
+
+	report := verify.Verify([][]seaweed.Forecast{{forecastAt(day, 5, 20)}}, truth)
+	if len(report) != 1 || report[0].N != 1 {
+		t.Errorf("expected the forecast to be verified against the observation; got '%v'", report)
+	}
+
+	if len(report) == 1 && report[0].Ratings != 0 {
+		t.Errorf("expected no rating to be verified against the observation; got '%d'", report[0].Ratings)
+	}
+}
+
+func TestObservation_Forecast_missing(t *testing.T) {
+	o := newObservation(time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC))
+	o.WaveHeight = 1.3
+
+	f, vars := o.Forecast("m", "kph", "c")
+	if vars != verify.Height {
+		t.Errorf("expected only the observed height; got '%b'", vars)
+	}
+
+	if c := f.Swell.Components.Combined; c.Height != 1.3 || c.Period != 0 || c.CompassDirection != "" {
+		t.Errorf("expected waves without period or direction; got '%+v'", c)
+	}
+
+	if f.Wind.Speed != 0 || f.Wind.CompassDirection != "" || f.Wind.Unit != "kph" {
+		t.Errorf("expected wind without speed or direction; got '%+v'", f.Wind)
+	}
+}
@@ -0,0 +1,264 @@
+// Package ndbc fetches and parses NOAA National Data Buoy Center (NDBC)
+// realtime observations, and aligns them with forecasts for verification and
+// display.
+//
+// See https://www.ndbc.noaa.gov/faq/measdes.shtml for the definitions of the
+// measurements.
+package ndbc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// missing is the marker NDBC uses for missing measurements.
+const missing = "MM"
+
+// Value is a measurement that may be missing, in which case it is NaN.
+type Value float64
+
+// Missing is a missing Value.
+var Missing = Value(math.NaN())
+
+// Valid returns true if the value is not missing.
+func (v Value) Valid() bool {
+	return !math.IsNaN(float64(v))
+}
+
+// MarshalJSON encodes a missing value as null.
+func (v Value) MarshalJSON() ([]byte, error) {
+	if !v.Valid() {
+		return []byte("null"), nil
+	}
+
+	return []byte(strconv.FormatFloat(float64(v), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON decodes null as a missing value.
+func (v *Value) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*v = Missing
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+	*v = Value(f)
+
+	return nil
+}
+
+// Observation is a buoy's measurements at a time, in NDBC's units. Standard
+// meteorological (.txt) and spectral wave summary (.spec) files report
+// different subsets of the measurements; those a file omits are Missing.
+type Observation struct {
+	// Time is the time of the observation.
+	Time time.Time `json:"time"`
+
+	// WindDirection is the direction the wind comes from, in degrees
+	// clockwise from true north (WDIR).
+	WindDirection Value `json:"windDirection"`
+	// WindSpeed is the average wind speed, in meters per second (WSPD).
+	WindSpeed Value `json:"windSpeed"`
+	// WindGust is the peak gust speed, in meters per second (GST).
+	WindGust Value `json:"windGust"`
+
+	// WaveHeight is the significant wave height, in meters (WVHT).
+	WaveHeight Value `json:"waveHeight"`
+	// DominantPeriod is the dominant wave period, in seconds (DPD).
+	DominantPeriod Value `json:"dominantPeriod"`
+	// AveragePeriod is the average wave period, in seconds (APD).
+	AveragePeriod Value `json:"averagePeriod"`
+	// MeanWaveDirection is the direction the dominant waves come from, in
+	// degrees clockwise from true north (MWD).
+	MeanWaveDirection Value `json:"meanWaveDirection"`
+
+	// SwellHeight is the swell height, in meters (SwH).
+	SwellHeight Value `json:"swellHeight"`
+	// SwellPeriod is the swell period, in seconds (SwP).
+	SwellPeriod Value `json:"swellPeriod"`
+	// SwellDirection is the compass point the swell comes from (SwD).
+	SwellDirection string `json:"swellDirection,omitempty"`
+	// WindWaveHeight is the wind wave height, in meters (WWH).
+	WindWaveHeight Value `json:"windWaveHeight"`
+	// WindWavePeriod is the wind wave period, in seconds (WWP).
+	WindWavePeriod Value `json:"windWavePeriod"`
+	// WindWaveDirection is the compass point the wind waves come from (WWD).
+	WindWaveDirection string `json:"windWaveDirection,omitempty"`
+	// Steepness is the wave steepness, such as "AVERAGE" (STEEPNESS).
+	Steepness string `json:"steepness,omitempty"`
+
+	// Pressure is the sea level pressure, in hectopascals (PRES).
+	Pressure Value `json:"pressure"`
+	// AirTemperature is the air temperature, in degrees Celsius (ATMP).
+	AirTemperature Value `json:"airTemperature"`
+	// WaterTemperature is the sea surface temperature, in degrees Celsius
+	// (WTMP).
+	WaterTemperature Value `json:"waterTemperature"`
+}
+
+// newObservation returns an Observation whose measurements are all Missing.
+func newObservation(t time.Time) Observation {
+	return Observation{
+		Time:              t,
+		WindDirection:     Missing,
+		WindSpeed:         Missing,
+		WindGust:          Missing,
+		WaveHeight:        Missing,
+		DominantPeriod:    Missing,
+		AveragePeriod:     Missing,
+		MeanWaveDirection: Missing,
+		SwellHeight:       Missing,
+		SwellPeriod:       Missing,
+		WindWaveHeight:    Missing,
+		WindWavePeriod:    Missing,
+		Pressure:          Missing,
+		AirTemperature:    Missing,
+		WaterTemperature:  Missing,
+	}
+}
+
+// values maps NDBC column names to the Observation fields they populate.
+var values = map[string]func(o *Observation) *Value{
+	"WDIR": func(o *Observation) *Value { return &o.WindDirection },
+	"WSPD": func(o *Observation) *Value { return &o.WindSpeed },
+	"GST":  func(o *Observation) *Value { return &o.WindGust },
+	"WVHT": func(o *Observation) *Value { return &o.WaveHeight },
+	"DPD":  func(o *Observation) *Value { return &o.DominantPeriod },
+	"APD":  func(o *Observation) *Value { return &o.AveragePeriod },
+	"MWD":  func(o *Observation) *Value { return &o.MeanWaveDirection },
+	"SwH":  func(o *Observation) *Value { return &o.SwellHeight },
+	"SwP":  func(o *Observation) *Value { return &o.SwellPeriod },
+	"WWH":  func(o *Observation) *Value { return &o.WindWaveHeight },
+	"WWP":  func(o *Observation) *Value { return &o.WindWavePeriod },
+	"PRES": func(o *Observation) *Value { return &o.Pressure },
+	"ATMP": func(o *Observation) *Value { return &o.AirTemperature },
+	"WTMP": func(o *Observation) *Value { return &o.WaterTemperature },
+}
+
+// texts maps NDBC column names to the textual Observation fields they
+// populate.
+var texts = map[string]func(o *Observation) *string{
+	"SwD":       func(o *Observation) *string { return &o.SwellDirection },
+	"WWD":       func(o *Observation) *string { return &o.WindWaveDirection },
+	"STEEPNESS": func(o *Observation) *string { return &o.Steepness },
+}
+
+// timeColumns are the columns from which an observation's time is parsed.
+var timeColumns = []string{"YY", "MM", "DD", "hh", "mm"}
+
+// Parse parses an NDBC realtime2 standard meteorological (.txt) or spectral
+// wave summary (.spec) file into observations, ordered by time. Columns are
+// identified by the file's header; unrecognized columns are ignored.
+func Parse(r io.Reader) ([]Observation, error) {
+	var columns []string
+	var observations []Observation
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "#") {
+			// The first comment line names the columns; the second reports
+			// their units.
+			if columns == nil {
+				columns = strings.Fields(strings.TrimPrefix(text, "#"))
+				if err := checkTimeColumns(columns); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		}
+
+		if columns == nil {
+			return nil, fmt.Errorf("line %d: data precedes header", line)
+		}
+
+		o, err := parseRow(columns, strings.Fields(text))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		observations = append(observations, o)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// NDBC files list the most recent observations first.
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].Time.Before(observations[j].Time)
+	})
+
+	return observations, nil
+}
+
+func checkTimeColumns(columns []string) error {
+	for i, name := range timeColumns {
+		if i >= len(columns) || columns[i] != name {
+			return fmt.Errorf("unexpected header %q: expected columns to begin %s", strings.Join(columns, " "), strings.Join(timeColumns, " "))
+		}
+	}
+
+	return nil
+}
+
+func parseRow(columns, fields []string) (Observation, error) {
+	if len(fields) != len(columns) {
+		return Observation{}, fmt.Errorf("expected %d fields; got %d", len(columns), len(fields))
+	}
+
+	var parts [5]int
+	for i := range timeColumns {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return Observation{}, fmt.Errorf("invalid %s %q", timeColumns[i], fields[i])
+		}
+		parts[i] = n
+	}
+
+	// Two-digit years predate 1999.
+	if parts[0] < 100 {
+		parts[0] += 1900
+	}
+
+	o := newObservation(time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], 0, 0, time.UTC))
+
+	for i := len(timeColumns); i < len(columns); i++ {
+		field := fields[i]
+
+		if text, ok := texts[columns[i]]; ok {
+			if field != missing {
+				*text(&o) = field
+			}
+
+			continue
+		}
+
+		value, ok := values[columns[i]]
+		if !ok || field == missing {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return Observation{}, fmt.Errorf("invalid %s %q", columns[i], field)
+		}
+		*value(&o) = Value(f)
+	}
+
+	return o, nil
+}
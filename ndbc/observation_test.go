@@ -0,0 +1,165 @@
+package ndbc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseFixture(t *testing.T, name string) []Observation {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	observations, err := Parse(f)
+	if err != nil {
+		t.Fatalf("expected Parse not to error; got '%v'", err)
+	}
+
+	return observations
+}
+
+func TestParse_standard(t *testing.T) {
+	observations := parseFixture(t, "44009.txt")
+
+	if len(observations) != 5 {
+		t.Fatalf("expected '5' observations; got '%d'", len(observations))
+	}
+
+	first := observations[0]
+	if expect := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC); !first.Time.Equal(expect) {
+		t.Errorf("expected observations ordered by time, beginning '%s'; got '%s'", expect, first.Time)
+	}
+
+	tests := []struct {
+		desc   string
+		got    Value
+		expect float64
+	}{
+		{"WDIR", first.WindDirection, 350},
+		{"WSPD", first.WindSpeed, 8},
+		{"GST", first.WindGust, 10},
+		{"WVHT", first.WaveHeight, 1.6},
+		{"DPD", first.DominantPeriod, 9},
+		{"APD", first.AveragePeriod, 5.9},
+		{"MWD", first.MeanWaveDirection, 125},
+		{"PRES", first.Pressure, 1018.4},
+		{"ATMP", first.AirTemperature, 15.3},
+		{"WTMP", first.WaterTemperature, 17.5},
+	}
+
+	for _, test := range tests {
+		if float64(test.got) != test.expect {
+			t.Errorf("expected %s '%g'; got '%g'", test.desc, test.expect, test.got)
+		}
+	}
+
+	last := observations[4]
+	if last.WaveHeight.Valid() || last.DominantPeriod.Valid() || last.MeanWaveDirection.Valid() {
+		t.Errorf("expected MM wave measurements to be missing; got '%+v'", last)
+	}
+
+	if last.SwellHeight.Valid() {
+		t.Errorf("expected measurements absent from the format to be missing; got '%g'", last.SwellHeight)
+	}
+}
+
+func TestParse_spectral(t *testing.T) {
+	observations := parseFixture(t, "44009.spec")
+
+	if len(observations) != 3 {
+		t.Fatalf("expected '3' observations; got '%d'", len(observations))
+	}
+
+	o := observations[1]
+	if o.WaveHeight != 1.4 || o.SwellHeight != 1.2 || o.SwellPeriod != 9.1 || o.WindWaveHeight != 0.7 || o.WindWavePeriod != 4.5 || o.MeanWaveDirection != 121 {
+		t.Errorf("expected spectral measurements; got '%+v'", o)
+	}
+
+	if o.SwellDirection != "SE" || o.WindWaveDirection != "NNW" || o.Steepness != "STEEP" {
+		t.Errorf("expected textual measurements; got '%+v'", o)
+	}
+
+	if o.WindSpeed.Valid() {
+		t.Errorf("expected measurements absent from the format to be missing; got '%g'", o.WindSpeed)
+	}
+
+	if first := observations[0]; first.SwellHeight.Valid() || first.SwellDirection != "" || first.Steepness != "" {
+		t.Errorf("expected MM measurements to be missing; got '%+v'", first)
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		desc        string
+		input       string
+		expectError string
+	}{{
+		desc:        "data preceding the header",
+		input:       "2026 10 18 00 00 1.6\n",
+		expectError: "line 1: data precedes header",
+	}, {
+		desc:        "unexpected header",
+		input:       "#STN WVHT\n",
+		expectError: `unexpected header "STN WVHT": expected columns to begin YY MM DD hh mm`,
+	}, {
+		desc:        "wrong number of fields",
+		input:       "#YY MM DD hh mm WVHT\n2026 10 18 00 00\n",
+		expectError: "line 2: expected 6 fields; got 5",
+	}, {
+		desc:        "invalid time",
+		input:       "#YY MM DD hh mm WVHT\n2026 Oct 18 00 00 1.6\n",
+		expectError: `line 2: invalid MM "Oct"`,
+	}, {
+		desc:        "invalid value",
+		input:       "#YY MM DD hh mm WVHT\n2026 10 18 00 00 big\n",
+		expectError: `line 2: invalid WVHT "big"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(test.input))
+			if err == nil || err.Error() != test.expectError {
+				t.Errorf("expected '%s'; got '%v'", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestParse_twoDigitYears(t *testing.T) {
+	observations, err := Parse(strings.NewReader("#YY MM DD hh mm WVHT\n98 01 02 03 04 1.0\n"))
+	if err != nil {
+		t.Fatalf("expected Parse not to error; got '%v'", err)
+	}
+
+	if expect := time.Date(1998, 1, 2, 3, 4, 0, 0, time.UTC); !observations[0].Time.Equal(expect) {
+		t.Errorf("expected '%s'; got '%s'", expect, observations[0].Time)
+	}
+}
+
+func TestValueJSON(t *testing.T) {
+	b, err := json.Marshal([]Value{1.5, Missing})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "[1.5,null]" {
+		t.Errorf("expected '[1.5,null]'; got '%s'", b)
+	}
+
+	var values []Value
+	if err := json.Unmarshal(b, &values); err != nil {
+		t.Fatal(err)
+	}
+
+	if values[0] != 1.5 || values[1].Valid() {
+		t.Errorf("expected '[1.5 NaN]'; got '%v'", values)
+	}
+}
@@ -0,0 +1,5 @@
+#YY  MM DD hh mm WVHT  SwH  SwP  WWH  WWP SwD WWD  STEEPNESS  APD MWD
+#yr  mo dy hr mn    m    m  sec    m  sec  -  degT     -      sec degT
+2026 10 18 06 00  1.3  1.1 10.0  0.6  4.3 ESE  NW    AVERAGE  6.2 118
+2026 10 18 03 00  1.4  1.2  9.1  0.7  4.5  SE NNW      STEEP  6.0 121
+2026 10 18 00 00  1.6   MM   MM  0.9  4.8  MM   N         MM  5.9 125
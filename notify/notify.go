@@ -0,0 +1,102 @@
+// Package notify sends messages describing Magic Seaweed forecasts to chat
+// services, such as Slack and Discord, via JSON webhooks.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// Message describes a window of forecasts for a spot.
+type Message struct {
+	// Title summarizes the message, such as "Good surf this weekend".
+	Title string
+	// Spot names the forecasted spot, such as "Ocean City, NJ".
+	Spot string
+	// Forecasts are the forecasts the message describes.
+	Forecasts []seaweed.Forecast
+}
+
+// Notifier sends messages.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Lines renders each of the message's forecasts as a line of text, including
+// its local time, star rating, breaking height, swell components, and wind.
+func (m Message) Lines() []string {
+	lines := make([]string, len(m.Forecasts))
+	for i, f := range m.Forecasts {
+		lines[i] = line(f)
+	}
+
+	return lines
+}
+
+func line(f seaweed.Forecast) string {
+	// LocalTimestamp is the spot's local time expressed as a Unix timestamp.
+	t := time.Unix(int64(f.LocalTimestamp), 0).UTC()
+	unit := f.Swell.Unit
+
+	parts := []string{
+		t.Format("Mon Jan 2 15:04"),
+		seaweed.Stars(f),
+		fmt.Sprintf("%d-%d%s", f.Swell.MinBreakingHeight, f.Swell.MaxBreakingHeight, unit),
+		"primary " + component(f.Swell.Components.Primary, unit),
+	}
+
+	if c := f.Swell.Components.Secondary; c != nil {
+		parts = append(parts, "secondary "+component(*c, unit))
+	}
+
+	if c := f.Swell.Components.Tertiary; c != nil {
+		parts = append(parts, "tertiary "+component(*c, unit))
+	}
+
+	parts = append(parts, fmt.Sprintf("wind %d%s %s gusting %d", f.Wind.Speed, f.Wind.Unit, f.Wind.CompassDirection, f.Wind.Gusts))
+
+	return strings.Join(parts, " · ")
+}
+
+func component(c seaweed.Component, unit string) string {
+	return fmt.Sprintf("%g%s @ %ds %s", c.Height, unit, c.Period, c.CompassDirection)
+}
+
+// Heading returns the message's title and spot, joined.
+func (m Message) Heading() string {
+	switch {
+	case m.Title == "":
+		return m.Spot
+	case m.Spot == "":
+		return m.Title
+	default:
+		return m.Title + ": " + m.Spot
+	}
+}
+
+// ChartURL returns the swell chart image URL of the message's first forecast,
+// or an empty string if there is none.
+func (m Message) ChartURL() string {
+	for _, f := range m.Forecasts {
+		if f.Charts.Swell != "" {
+			return f.Charts.Swell
+		}
+	}
+
+	return ""
+}
+
+// truncate shortens s to at most max runes, marking truncation with an
+// ellipsis.
+func truncate(s string, max int) string {
+	rs := []rune(s)
+	if len(rs) <= max {
+		return s
+	}
+
+	return string(rs[:max-1]) + "…"
+}
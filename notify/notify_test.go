@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+var forecasts []seaweed.Forecast
+
+func TestMain(m *testing.M) {
+	content, err := ioutil.ReadFile("../testdata/response.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := json.Unmarshal(content, &forecasts); err != nil {
+		log.Fatal(err)
+	}
+
+	exitVal := m.Run()
+	os.Exit(exitVal)
+}
+
+func TestMessage(t *testing.T) {
+	f := forecasts[0]
+	f.Swell.Components.Secondary = &seaweed.Component{Height: 2, Period: 6, CompassDirection: "E"}
+	msg := Message{Title: "Good surf", Spot: "Ocean City, NJ", Forecasts: []seaweed.Forecast{f}}
+
+	if msg.Heading() != "Good surf: Ocean City, NJ" {
+		t.Errorf("unexpected heading '%s'", msg.Heading())
+	}
+
+	expected := "Tue Sep 15 22:15 · ☆☆☆ · 5-8ft · primary 7.5ft @ 10s SE · secondary 2ft @ 6s E · wind 13mph SSE gusting 27"
+	if lines := msg.Lines(); len(lines) != 1 || lines[0] != expected {
+		t.Errorf("expected lines '[%s]'; got '%v'", expected, lines)
+	}
+
+	if msg.ChartURL() != "http://hist-2.msw.ms/wave/750/20-1443592800-1.gif" {
+		t.Errorf("unexpected chart URL '%s'", msg.ChartURL())
+	}
+}
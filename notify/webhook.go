@@ -0,0 +1,263 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of times a Webhook retries a
+	// failed delivery.
+	DefaultMaxRetries = 3
+	// DefaultBackoff is the default delay before a Webhook's first retry; the
+	// delay doubles with each subsequent retry.
+	DefaultBackoff = time.Second
+)
+
+// Formatter renders a Message as a webhook's JSON request body.
+type Formatter interface {
+	Format(msg Message) ([]byte, error)
+}
+
+// Webhook is a Notifier posting JSON-formatted messages to a webhook URL,
+// retrying deliveries that fail due to transport errors, rate limiting, or
+// server errors.
+type Webhook struct {
+	// url is the webhook URL.
+	url string
+	// formatter renders messages as request bodies.
+	formatter Formatter
+	// httpClient is a *http.Client.
+	httpClient *http.Client
+	// maxRetries is the maximum number of retries of a failed delivery.
+	maxRetries int
+	// backoff is the delay before the first retry.
+	backoff time.Duration
+}
+
+// WebhookOption configures one or more Webhook fields.
+type WebhookOption = func(w *Webhook)
+
+// WithHTTPClient is a WebhookOption to configure a *Webhook's httpClient.
+func WithHTTPClient(httpClient *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.httpClient = httpClient
+	}
+}
+
+// WithRetries is a WebhookOption to configure how many times a *Webhook
+// retries a failed delivery, and the delay before its first retry.
+func WithRetries(maxRetries int, backoff time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		w.maxRetries = maxRetries
+		w.backoff = backoff
+	}
+}
+
+// NewWebhook returns a *Webhook posting messages rendered by formatter to url.
+func NewWebhook(url string, formatter Formatter, opts ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url:        url,
+		formatter:  formatter,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// NewSlack returns a *Webhook posting Slack-formatted messages to a Slack
+// incoming webhook URL.
+func NewSlack(url string, opts ...WebhookOption) *Webhook {
+	return NewWebhook(url, SlackFormatter{}, opts...)
+}
+
+// NewDiscord returns a *Webhook posting Discord-formatted messages to a
+// Discord webhook URL.
+func NewDiscord(url string, opts ...WebhookOption) *Webhook {
+	return NewWebhook(url, DiscordFormatter{}, opts...)
+}
+
+// Notify implements Notifier.
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	body, err := w.formatter.Format(msg)
+	if err != nil {
+		return err
+	}
+
+	delay := w.backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if attempt >= w.maxRetries {
+			return err
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+	}
+}
+
+// post delivers body, returning the delay requested by a Retry-After response
+// header, if any.
+func (w *Webhook) post(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, &permanentError{ctx.Err()}
+		}
+
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return retryAfter(resp.Header), fmt.Errorf("webhook returned HTTP status code %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	default:
+		return 0, &permanentError{fmt.Errorf("webhook returned HTTP status code %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))}
+	}
+}
+
+// retryAfter parses a Retry-After header expressed in seconds.
+func retryAfter(h http.Header) time.Duration {
+	secs, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+
+	return time.Duration(secs) * time.Second
+}
+
+// permanentError is a delivery failure that will not succeed if retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// SlackFormatter formats messages as Slack incoming webhook payloads.
+type SlackFormatter struct{}
+
+// slackTextLimit is the maximum length of a Slack section block's text.
+const slackTextLimit = 3000
+
+// slackEscaper escapes the characters Slack reserves for its mrkdwn control
+// sequences, such as <https://example.com|links>.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Format implements Formatter.
+func (SlackFormatter) Format(msg Message) ([]byte, error) {
+	heading := slackEscaper.Replace(msg.Heading())
+	text := "*" + heading + "*\n" + slackEscaper.Replace(strings.Join(msg.Lines(), "\n"))
+
+	blocks := []map[string]interface{}{{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": truncateEscaped(text, slackTextLimit)},
+	}}
+
+	if u := msg.ChartURL(); u != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type":      "image",
+			"image_url": u,
+			"alt_text":  "swell chart",
+		})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"text":   heading,
+		"blocks": blocks,
+	})
+}
+
+// truncateEscaped truncates escaped text per truncate, without leaving a
+// partial escape sequence before the ellipsis.
+func truncateEscaped(s string, max int) string {
+	t := truncate(s, max)
+	if t == s {
+		return s
+	}
+
+	body := strings.TrimSuffix(t, "…")
+	if i := strings.LastIndexByte(body, '&'); i > strings.LastIndexByte(body, ';') {
+		body = body[:i]
+	}
+
+	return body + "…"
+}
+
+// DiscordFormatter formats messages as Discord webhook payloads.
+type DiscordFormatter struct{}
+
+const (
+	// discordTitleLimit and discordDescriptionLimit are the maximum lengths of
+	// a Discord embed's title and description.
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+)
+
+// Format implements Formatter.
+func (DiscordFormatter) Format(msg Message) ([]byte, error) {
+	embed := map[string]interface{}{
+		"title":       truncate(msg.Heading(), discordTitleLimit),
+		"description": truncate(strings.Join(msg.Lines(), "\n"), discordDescriptionLimit),
+	}
+
+	if u := msg.ChartURL(); u != "" {
+		embed["image"] = map[string]string{"url": u}
+	}
+
+	// Parse no mentions, such as @everyone in a title or spot name, so that
+	// messages never ping.
+	return json.Marshal(map[string]interface{}{
+		"embeds":           []interface{}{embed},
+		"allowed_mentions": map[string][]string{"parse": {}},
+	})
+}
@@ -0,0 +1,228 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is a local webhook receiver responding with each of codes in turn,
+// and recording the bodies it receives.
+type receiver struct {
+	mu     sync.Mutex
+	codes  []int
+	bodies []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code := http.StatusOK
+	if len(r.bodies) < len(r.codes) {
+		code = r.codes[len(r.bodies)]
+	}
+	r.bodies = append(r.bodies, string(body))
+
+	if req.Header.Get("Content-Type") != "application/json" {
+		code = http.StatusUnsupportedMediaType
+	}
+
+	w.WriteHeader(code)
+}
+
+func TestWebhook_Notify(t *testing.T) {
+	tests := []struct {
+		desc          string
+		codes         []int
+		expectError   string
+		expectAttempt int
+	}{{
+		desc:          "when delivery succeeds",
+		expectAttempt: 1,
+	}, {
+		desc:          "when delivery succeeds after retries",
+		codes:         []int{500, 429, 200},
+		expectAttempt: 3,
+	}, {
+		desc:          "when delivery exhausts its retries",
+		codes:         []int{502, 502, 502},
+		expectError:   "webhook returned HTTP status code 502: ",
+		expectAttempt: 3,
+	}, {
+		desc:          "when delivery fails permanently",
+		codes:         []int{400},
+		expectError:   "webhook returned HTTP status code 400: ",
+		expectAttempt: 1,
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r := &receiver{codes: test.codes}
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			w := NewSlack(server.URL, WithHTTPClient(server.Client()), WithRetries(2, time.Millisecond))
+			err := w.Notify(context.Background(), Message{Title: "Good surf", Forecasts: forecasts})
+
+			if test.expectError == "" && err != nil {
+				t.Errorf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if test.expectError != "" && (err == nil || err.Error() != test.expectError) {
+				t.Errorf("expected error '%s'; got '%v'", test.expectError, err)
+			}
+
+			if len(r.bodies) != test.expectAttempt {
+				t.Errorf("expected '%d' attempts; got '%d'", test.expectAttempt, len(r.bodies))
+			}
+		})
+	}
+}
+
+func TestWebhook_Notify_canceled(t *testing.T) {
+	r := &receiver{codes: []int{500, 500}}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := NewDiscord(server.URL, WithHTTPClient(server.Client()), WithRetries(5, time.Hour))
+	if err := w.Notify(ctx, Message{Forecasts: forecasts}); err != context.Canceled {
+		t.Errorf("expected '%v'; got '%v'", context.Canceled, err)
+	}
+}
+
+func TestSlackFormatter(t *testing.T) {
+	body, err := SlackFormatter{}.Format(Message{Title: "Good surf", Spot: "Ocean City, NJ", Forecasts: forecasts[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type     string `json:"type"`
+			ImageURL string `json:"image_url"`
+			Text     struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Text != "Good surf: Ocean City, NJ" {
+		t.Errorf("unexpected fallback text '%s'", payload.Text)
+	}
+
+	if len(payload.Blocks) != 2 || payload.Blocks[0].Type != "section" || payload.Blocks[1].Type != "image" {
+		t.Fatalf("expected a section and an image block; got '%s'", body)
+	}
+
+	if !strings.HasPrefix(payload.Blocks[0].Text.Text, "*Good surf: Ocean City, NJ*\nTue Sep 15 22:15 · ☆☆☆ · 5-8ft") {
+		t.Errorf("unexpected section text '%s'", payload.Blocks[0].Text.Text)
+	}
+
+	if payload.Blocks[1].ImageURL != "http://hist-2.msw.ms/wave/750/20-1443592800-1.gif" {
+		t.Errorf("unexpected image URL '%s'", payload.Blocks[1].ImageURL)
+	}
+}
+
+func TestSlackFormatter_escaping(t *testing.T) {
+	body, err := SlackFormatter{}.Format(Message{Title: "<!channel> Surf & wind", Spot: "Bay <Head>", Forecasts: forecasts[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "&lt;!channel&gt; Surf &amp; wind: Bay &lt;Head&gt;"
+	if payload.Text != expect {
+		t.Errorf("expected fallback text '%s'; got '%s'", expect, payload.Text)
+	}
+
+	if !strings.HasPrefix(payload.Blocks[0].Text.Text, "*"+expect+"*\n") {
+		t.Errorf("expected section text to begin '*%s*'; got '%s'", expect, payload.Blocks[0].Text.Text)
+	}
+}
+
+func TestTruncateEscaped(t *testing.T) {
+	tests := []struct {
+		s      string
+		max    int
+		expect string
+	}{
+		{"a &amp; b", 20, "a &amp; b"},
+		{"a &amp; b", 5, "a …"},
+		{"a &amp; b", 8, "a &amp;…"},
+		{"abcdef", 4, "abc…"},
+	}
+
+	for _, test := range tests {
+		if got := truncateEscaped(test.s, test.max); got != test.expect {
+			t.Errorf("expected truncateEscaped(%q, %d) to be '%s'; got '%s'", test.s, test.max, test.expect, got)
+		}
+	}
+}
+
+func TestDiscordFormatter(t *testing.T) {
+	body, err := DiscordFormatter{}.Format(Message{Title: "Good surf", Forecasts: forecasts})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		AllowedMentions *struct {
+			Parse []string `json:"parse"`
+		} `json:"allowed_mentions"`
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Image       struct {
+				URL string `json:"url"`
+			} `json:"image"`
+		} `json:"embeds"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("expected '1' embed; got '%s'", body)
+	}
+
+	e := payload.Embeds[0]
+	if e.Title != "Good surf" || strings.Count(e.Description, "\n") != 2 || e.Image.URL == "" {
+		t.Errorf("unexpected embed '%+v'", e)
+	}
+
+	if !strings.Contains(string(body), `"allowed_mentions":{"parse":[]}`) || payload.AllowedMentions == nil {
+		t.Errorf("expected no mentions to be parsed; got '%s'", body)
+	}
+}
@@ -0,0 +1,433 @@
+// Package openmeteo provides a seaweed.Provider fetching forecasts from the
+// keyless Open-Meteo marine and weather APIs, such that seaweed.Client and its
+// downstream tooling keep working without a Magic Seaweed API key:
+//
+//	client := seaweed.NewClient("", seaweed.WithProvider(openmeteo.New()))
+//	forecasts, err := client.Today("39.277,-74.574")
+//
+// See https://open-meteo.com/en/docs/marine-weather-api.
+package openmeteo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+const (
+	// DefaultMarineURL is the Open-Meteo marine API forecast endpoint.
+	DefaultMarineURL = "https://marine-api.open-meteo.com/v1/marine"
+	// DefaultWeatherURL is the Open-Meteo weather API forecast endpoint.
+	DefaultWeatherURL = "https://api.open-meteo.com/v1/forecast"
+)
+
+// marineVariables are the hourly marine variables the Provider requests.
+var marineVariables = []string{
+	"wave_height", "wave_direction", "wave_period",
+	"swell_wave_height", "swell_wave_direction", "swell_wave_period",
+	"secondary_swell_wave_height", "secondary_swell_wave_direction", "secondary_swell_wave_period",
+}
+
+// weatherVariables are the hourly weather variables the Provider requests.
+var weatherVariables = []string{
+	"temperature_2m", "pressure_msl", "wind_speed_10m", "wind_direction_10m", "wind_gusts_10m",
+}
+
+// Locator resolves a spot, as passed to Provider.Forecast, to a coordinate.
+type Locator = seaweed.Locator
+
+// Provider is a seaweed.Provider fetching forecasts from the Open-Meteo marine
+// and weather APIs.
+//
+// Open-Meteo reports neither star ratings nor model run times. The Provider's
+// forecasts are therefore unrated, and their IssueTimestamp is the time at
+// which they were fetched, truncated to the hour. Variables missing from a
+// timestep are left unset; a swell component or wind lacking a direction has
+// no CompassDirection.
+type Provider struct {
+	// marineURL is the marine API forecast endpoint.
+	marineURL string
+	// weatherURL is the weather API forecast endpoint.
+	weatherURL string
+	// httpClient is a *http.Client.
+	httpClient *http.Client
+	// units is the Magic Seaweed unit system in which forecasts are
+	// reported: "us", "uk", or "eu".
+	units string
+	// locate resolves spots to coordinates.
+	locate Locator
+	// clock reports the current time.
+	clock seaweed.Clock
+	// maxResponseBytes is the maximum number of response body bytes read from
+	// each endpoint.
+	maxResponseBytes int64
+}
+
+// Option configures one or more Provider fields.
+type Option = func(p *Provider)
+
+// WithMarineURL is an Option to configure a *Provider's marine API endpoint.
+func WithMarineURL(u string) Option {
+	return func(p *Provider) {
+		p.marineURL = u
+	}
+}
+
+// WithWeatherURL is an Option to configure a *Provider's weather API endpoint.
+func WithWeatherURL(u string) Option {
+	return func(p *Provider) {
+		p.weatherURL = u
+	}
+}
+
+// WithHTTPClient is an Option to configure a *Provider's httpClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(p *Provider) {
+		p.httpClient = httpClient
+	}
+}
+
+// WithUnits is an Option to configure the unit system in which a *Provider
+// reports forecasts, as per the Magic Seaweed API: "us" (feet, mph,
+// Fahrenheit), "uk" (feet, mph, Celsius), or "eu" (meters, kph, Celsius). It
+// defaults to "us".
+func WithUnits(units string) Option {
+	return func(p *Provider) {
+		p.units = units
+	}
+}
+
+// WithLocator is an Option to configure how a *Provider resolves spots to
+// coordinates, such as by looking Magic Seaweed spot IDs up in a spot catalog.
+// By default, spots are expected to be "lat,lon" coordinates; see ParseLatLon.
+func WithLocator(locate Locator) Option {
+	return func(p *Provider) {
+		p.locate = locate
+	}
+}
+
+// WithClock is an Option to configure a *Provider's clock.
+func WithClock(clock seaweed.Clock) Option {
+	return func(p *Provider) {
+		p.clock = clock
+	}
+}
+
+// WithMaxResponseBytes is an Option to configure the maximum number of
+// response body bytes a *Provider reads from each endpoint before failing with
+// seaweed.ErrResponseTooLarge. It defaults to seaweed.DefaultMaxResponseBytes;
+// a non-positive n is ignored.
+func WithMaxResponseBytes(n int64) Option {
+	return func(p *Provider) {
+		if n > 0 {
+			p.maxResponseBytes = n
+		}
+	}
+}
+
+// New returns a *Provider.
+func New(opts ...Option) *Provider {
+	p := &Provider{
+		marineURL:        DefaultMarineURL,
+		weatherURL:       DefaultWeatherURL,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		units:            "us",
+		locate:           ParseLatLon,
+		clock:            seaweed.RealClock{},
+		maxResponseBytes: seaweed.DefaultMaxResponseBytes,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// ParseLatLon is a Locator parsing spots of the form "lat,lon", such as
+// "39.277,-74.574".
+func ParseLatLon(spot string) (float64, float64, error) {
+	parts := strings.Split(spot, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid coordinate %q: expected lat,lon", spot)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("invalid coordinate %q: invalid latitude", spot)
+	}
+
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("invalid coordinate %q: invalid longitude", spot)
+	}
+
+	return lat, lon, nil
+}
+
+// units are the Open-Meteo length, wind speed and temperature units of a
+// Magic Seaweed unit system, and the corresponding Magic Seaweed unit strings.
+type units struct {
+	length, windSpeed, temperature       string
+	swellUnit, windUnit, temperatureUnit string
+}
+
+// unitSystem returns the units of a Magic Seaweed unit system.
+func unitSystem(system string) (units, error) {
+	switch system {
+	case "", "us":
+		return units{"imperial", "mph", "fahrenheit", "ft", "mph", "f"}, nil
+	case "uk":
+		return units{"imperial", "mph", "celsius", "ft", "mph", "c"}, nil
+	case "eu":
+		return units{"metric", "kmh", "celsius", "m", "kph", "c"}, nil
+	default:
+		return units{}, fmt.Errorf("unknown unit system %q", system)
+	}
+}
+
+// APIError is an error reported by an Open-Meteo API.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Reason is the reason the API reported.
+	Reason string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("open-meteo returned HTTP status code %d: %s", e.StatusCode, e.Reason)
+}
+
+// response is an Open-Meteo response. Its hourly variables are keyed by name;
+// the "time" variable holds Unix timestamps, and missing values are null.
+type response struct {
+	UTCOffsetSeconds int64                 `json:"utc_offset_seconds"`
+	Hourly           map[string][]*float64 `json:"hourly"`
+}
+
+// value returns the named variable's value at index i, and false if it is
+// missing.
+func (r *response) value(name string, i int) (float64, bool) {
+	values := r.Hourly[name]
+	if i >= len(values) || values[i] == nil {
+		return 0, false
+	}
+
+	return *values[i], true
+}
+
+// Forecast implements seaweed.Provider, fetching the forecast for the
+// coordinate to which spot resolves.
+func (p *Provider) Forecast(spot string) ([]seaweed.Forecast, error) {
+	lat, lon, err := p.locate(spot)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := unitSystem(p.units)
+	if err != nil {
+		return nil, err
+	}
+
+	marine, err := p.get(p.marineURL, lat, lon, url.Values{
+		"hourly":      {strings.Join(marineVariables, ",")},
+		"length_unit": {u.length},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	weather, err := p.get(p.weatherURL, lat, lon, url.Values{
+		"hourly":           {strings.Join(weatherVariables, ",")},
+		"wind_speed_unit":  {u.windSpeed},
+		"temperature_unit": {u.temperature},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.forecasts(marine, weather, u), nil
+}
+
+// get fetches the hourly variables for the coordinate from endpoint.
+func (p *Provider) get(endpoint string, lat, lon float64, params url.Values) (*response, error) {
+	params.Set("latitude", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("longitude", strconv.FormatFloat(lon, 'f', -1, 64))
+	params.Set("timeformat", "unixtime")
+	params.Set("timezone", "auto")
+
+	resp, err := p.httpClient.Get(endpoint + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body := seaweed.LimitReader(resp.Body, p.maxResponseBytes)
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(body).Decode(&apiErr)
+
+		return nil, &APIError{StatusCode: resp.StatusCode, Reason: apiErr.Reason}
+	}
+
+	r := &response{}
+	if err := json.NewDecoder(body).Decode(r); err != nil {
+		return nil, fmt.Errorf("unexpected open-meteo response: %w", err)
+	}
+
+	if r.Hourly["time"] == nil {
+		return nil, errors.New("unexpected open-meteo response: no hourly times")
+	}
+
+	return r, nil
+}
+
+// forecasts maps the marine and weather responses to forecasts, one per
+// hourly marine timestep. Timesteps lacking a combined wave height, such as
+// those beyond the marine model's horizon, are skipped.
+func (p *Provider) forecasts(marine, weather *response, u units) []seaweed.Forecast {
+	weatherIndex := map[int64]int{}
+	for i, t := range weather.Hourly["time"] {
+		if t != nil {
+			weatherIndex[int64(*t)] = i
+		}
+	}
+
+	issued := p.clock.Now().Truncate(time.Hour).Unix()
+
+	var forecasts []seaweed.Forecast
+	for i, t := range marine.Hourly["time"] {
+		if t == nil {
+			continue
+		}
+
+		combined, ok := component(marine, "wave", i)
+		if !ok {
+			continue
+		}
+
+		ts := int64(*t)
+		f := seaweed.Forecast{
+			Timestamp:      seaweed.FlexInt64(ts),
+			LocalTimestamp: seaweed.FlexInt64(ts + marine.UTCOffsetSeconds),
+			IssueTimestamp: seaweed.FlexInt64(issued),
+		}
+
+		f.Swell.Unit = u.swellUnit
+		f.Swell.Components.Combined = combined
+		f.Swell.Components.Primary = combined
+		if primary, ok := component(marine, "swell_wave", i); ok {
+			f.Swell.Components.Primary = primary
+		}
+
+		if secondary, ok := component(marine, "secondary_swell_wave", i); ok {
+			f.Swell.Components.Secondary = &secondary
+		}
+
+		setBreakingHeight(&f.Swell)
+
+		if j, ok := weatherIndex[ts]; ok {
+			setWeather(&f, weather, j, u)
+		}
+
+		forecasts = append(forecasts, f)
+	}
+
+	return forecasts
+}
+
+// component returns the swell component of the named wave variables at index
+// i, and false if its height is missing.
+func component(r *response, name string, i int) (seaweed.Component, bool) {
+	height, ok := r.value(name+"_height", i)
+	if !ok {
+		return seaweed.Component{}, false
+	}
+
+	period, _ := r.value(name+"_period", i)
+	c := seaweed.Component{
+		Height: seaweed.FlexFloat(round(height, 2)),
+		Period: seaweed.FlexInt(math.Round(period)),
+	}
+
+	// Open-Meteo reports the direction waves come from, whereas Magic Seaweed
+	// reports the direction they travel toward alongside the compass point
+	// they come from. A missing direction leaves both unset.
+	if from, ok := r.value(name+"_direction", i); ok {
+		c.Direction = seaweed.FlexFloat(round(float64(seaweed.Direction(from).Opposite()), 2))
+		c.CompassDirection = seaweed.Direction(from).Compass()
+	}
+
+	return c, true
+}
+
+// setWeather sets the forecast's wind and condition from the weather response
+// at index i.
+func setWeather(f *seaweed.Forecast, weather *response, i int, u units) {
+	f.Wind.Unit = u.windUnit
+	if speed, ok := weather.value("wind_speed_10m", i); ok {
+		f.Wind.Speed = seaweed.FlexInt(math.Round(speed))
+	}
+
+	if gusts, ok := weather.value("wind_gusts_10m", i); ok {
+		f.Wind.Gusts = seaweed.FlexInt64(math.Round(gusts))
+	}
+
+	if from, ok := weather.value("wind_direction_10m", i); ok {
+		f.Wind.Direction = seaweed.FlexInt64(math.Round(float64(seaweed.Direction(from).Opposite())))
+		f.Wind.CompassDirection = seaweed.Direction(from).Compass()
+	}
+
+	f.Condition.Unit = u.temperatureUnit
+	f.Condition.UnitPressure = "mb"
+	if temperature, ok := weather.value("temperature_2m", i); ok {
+		f.Condition.Temperature = seaweed.FlexInt64(math.Round(temperature))
+	}
+
+	if pressure, ok := weather.value("pressure_msl", i); ok {
+		f.Condition.Pressure = seaweed.FlexInt64(math.Round(pressure))
+	}
+}
+
+// setBreakingHeight estimates the swell's breaking height range from its
+// primary component using Komar and Gaughan's (1972) empirical relation,
+// Hb = 0.39 g^(1/5) (T H0²)^(2/5), where H0 is the deep water wave height, in
+// meters, and T its period. The range spans from three quarters of the
+// estimate to the estimate itself, approximating the spread of breaking
+// heights along a beach.
+func setBreakingHeight(s *seaweed.Swell) {
+	h := float64(s.Components.Primary.Height)
+	if s.Unit == "ft" {
+		h *= metersPerFoot
+	}
+
+	hb := 0.39 * math.Pow(9.81, 0.2) * math.Pow(float64(s.Components.Primary.Period)*h*h, 0.4)
+	if s.Unit == "ft" {
+		hb /= metersPerFoot
+	}
+
+	s.AbsMaxBreakingHeight = seaweed.FlexFloat(round(hb, 2))
+	s.AbsMinBreakingHeight = seaweed.FlexFloat(round(0.75*hb, 2))
+	s.MaxBreakingHeight = seaweed.FlexInt(math.Round(float64(s.AbsMaxBreakingHeight)))
+	s.MinBreakingHeight = seaweed.FlexInt(math.Round(float64(s.AbsMinBreakingHeight)))
+}
+
+// metersPerFoot is the number of meters in a foot.
+const metersPerFoot = 0.3048
+
+func round(f float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+
+	return math.Round(f*p) / p
+}
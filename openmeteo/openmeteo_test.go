@@ -0,0 +1,297 @@
+package openmeteo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+type testClock struct{}
+
+func (testClock) Now() time.Time {
+	return time.Date(2026, 10, 17, 23, 42, 0, 0, time.UTC)
+}
+
+// testServerAndProvider returns a test server serving the recorded marine and
+// weather fixtures, recording the query of each request, and a *Provider
+// targeting it.
+func testServerAndProvider(t *testing.T, queries map[string]url.Values, opts ...Option) *Provider {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture := map[string]string{"/v1/marine": "marine.json", "/v1/forecast": "weather.json"}[r.URL.Path]
+		if queries != nil {
+			queries[r.URL.Path] = r.URL.Query()
+		}
+
+		body, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return New(append([]Option{
+		WithMarineURL(server.URL + "/v1/marine"),
+		WithWeatherURL(server.URL + "/v1/forecast"),
+		WithHTTPClient(server.Client()),
+		WithClock(testClock{}),
+	}, opts...)...)
+}
+
+func TestForecast(t *testing.T) {
+	queries := map[string]url.Values{}
+	p := testServerAndProvider(t, queries)
+
+	forecasts, err := p.Forecast("39.277,-74.574")
+	if err != nil {
+		t.Fatalf("expected Forecast not to error; got '%v'", err)
+	}
+
+	// The final marine timestep, beyond the model's horizon, is skipped.
+	if len(forecasts) != 5 {
+		t.Fatalf("expected '5' forecasts; got '%d'", len(forecasts))
+	}
+
+	expect := seaweed.Forecast{
+		Timestamp:      1792281600,
+		LocalTimestamp: 1792267200,
+		IssueTimestamp: 1792278000,
+		Swell: seaweed.Swell{
+			MinBreakingHeight:    4,
+			AbsMinBreakingHeight: 4.11,
+			MaxBreakingHeight:    5,
+			AbsMaxBreakingHeight: 5.48,
+			Unit:                 "ft",
+			Components: seaweed.Components{
+				Combined:  seaweed.Component{Height: 4.27, Period: 8, Direction: 292, CompassDirection: "ESE"},
+				Primary:   seaweed.Component{Height: 3.61, Period: 10, Direction: 305, CompassDirection: "SE"},
+				Secondary: &seaweed.Component{Height: 1.31, Period: 6, Direction: 260, CompassDirection: "E"},
+			},
+		},
+		Wind: seaweed.Wind{
+			Speed:            10,
+			Direction:        135,
+			CompassDirection: "NW",
+			Gusts:            18,
+			Unit:             "mph",
+		},
+		Condition: seaweed.Condition{
+			Pressure:     1018,
+			Temperature:  59,
+			Unit:         "f",
+			UnitPressure: "mb",
+		},
+	}
+
+	if !reflect.DeepEqual(forecasts[0], expect) {
+		t.Errorf("expected '%+v'; got '%+v'", expect, forecasts[0])
+	}
+
+	if forecasts[2].Swell.Components.Secondary != nil {
+		t.Errorf("expected no secondary swell where it is missing; got '%+v'", forecasts[2].Swell.Components.Secondary)
+	}
+
+	if forecasts[4].Wind.Speed != 0 || forecasts[4].Wind.Gusts != 14 {
+		t.Errorf("expected a missing wind speed alongside gusts of '14'; got '%+v'", forecasts[4].Wind)
+	}
+
+	if got := forecasts[3].Swell.Components.Primary; got.Direction != 0 || got.CompassDirection != "" || got.Height != 3.94 {
+		t.Errorf("expected a primary swell without direction where it is missing; got '%+v'", got)
+	}
+
+	if got := forecasts[3].Wind; got.Direction != 0 || got.CompassDirection != "" || got.Speed != 7 {
+		t.Errorf("expected wind without direction where it is missing; got '%+v'", got)
+	}
+
+	if err := seaweed.ValidateForecasts(forecasts); err != nil {
+		t.Errorf("expected forecasts to be valid; got '%v'", err)
+	}
+
+	for path, param := range map[string]string{"/v1/marine": "length_unit", "/v1/forecast": "wind_speed_unit"} {
+		q := queries[path]
+		if q.Get("latitude") != "39.277" || q.Get("longitude") != "-74.574" || q.Get("timeformat") != "unixtime" || q.Get(param) == "" {
+			t.Errorf("expected %s to be queried for the coordinate in unixtime with a %s; got '%v'", path, param, q)
+		}
+	}
+}
+
+func TestForecast_units(t *testing.T) {
+	tests := []struct {
+		units             string
+		expectLength      string
+		expectWindSpeed   string
+		expectTemperature string
+		expectSwellUnit   string
+		expectWindUnit    string
+	}{{
+		units:             "us",
+		expectLength:      "imperial",
+		expectWindSpeed:   "mph",
+		expectTemperature: "fahrenheit",
+		expectSwellUnit:   "ft",
+		expectWindUnit:    "mph",
+	}, {
+		units:             "uk",
+		expectLength:      "imperial",
+		expectWindSpeed:   "mph",
+		expectTemperature: "celsius",
+		expectSwellUnit:   "ft",
+		expectWindUnit:    "mph",
+	}, {
+		units:             "eu",
+		expectLength:      "metric",
+		expectWindSpeed:   "kmh",
+		expectTemperature: "celsius",
+		expectSwellUnit:   "m",
+		expectWindUnit:    "kph",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.units, func(t *testing.T) {
+			queries := map[string]url.Values{}
+			p := testServerAndProvider(t, queries, WithUnits(test.units))
+
+			forecasts, err := p.Forecast("39.277,-74.574")
+			if err != nil {
+				t.Fatalf("expected Forecast not to error; got '%v'", err)
+			}
+
+			if got := queries["/v1/marine"].Get("length_unit"); got != test.expectLength {
+				t.Errorf("expected length_unit '%s'; got '%s'", test.expectLength, got)
+			}
+
+			if got := queries["/v1/forecast"].Get("wind_speed_unit"); got != test.expectWindSpeed {
+				t.Errorf("expected wind_speed_unit '%s'; got '%s'", test.expectWindSpeed, got)
+			}
+
+			if got := queries["/v1/forecast"].Get("temperature_unit"); got != test.expectTemperature {
+				t.Errorf("expected temperature_unit '%s'; got '%s'", test.expectTemperature, got)
+			}
+
+			if got := forecasts[0].Swell.Unit; got != test.expectSwellUnit {
+				t.Errorf("expected swell unit '%s'; got '%s'", test.expectSwellUnit, got)
+			}
+
+			if got := forecasts[0].Wind.Unit; got != test.expectWindUnit {
+				t.Errorf("expected wind unit '%s'; got '%s'", test.expectWindUnit, got)
+			}
+		})
+	}
+}
+
+func TestForecast_errors(t *testing.T) {
+	tests := []struct {
+		desc        string
+		spot        string
+		opts        []Option
+		handler     http.HandlerFunc
+		expectError string
+	}{{
+		desc:        "invalid coordinate",
+		spot:        "391",
+		expectError: `invalid coordinate "391": expected lat,lon`,
+	}, {
+		desc:        "out of range latitude",
+		spot:        "91,0",
+		expectError: `invalid coordinate "91,0": invalid latitude`,
+	}, {
+		desc:        "unknown unit system",
+		spot:        "39.277,-74.574",
+		opts:        []Option{WithUnits("imperial")},
+		expectError: `unknown unit system "imperial"`,
+	}, {
+		desc: "API error",
+		spot: "39.277,-74.574",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":true,"reason":"Cannot initialize WeatherVariable from invalid String value foo"}`))
+		},
+		expectError: "open-meteo returned HTTP status code 400: Cannot initialize WeatherVariable from invalid String value foo",
+	}, {
+		desc: "invalid JSON",
+		spot: "39.277,-74.574",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"hourly":`))
+		},
+		expectError: "unexpected open-meteo response: unexpected EOF",
+	}, {
+		desc: "no hourly times",
+		spot: "39.277,-74.574",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"hourly":{}}`))
+		},
+		expectError: "unexpected open-meteo response: no hourly times",
+	}, {
+		desc: "response too large",
+		spot: "39.277,-74.574",
+		opts: []Option{WithMaxResponseBytes(8)},
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"hourly":{"time":[1792281600]}}`))
+		},
+		expectError: "unexpected open-meteo response: response body exceeds maximum size",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			opts := test.opts
+			if test.handler != nil {
+				server := httptest.NewTLSServer(test.handler)
+				defer server.Close()
+
+				opts = append(opts, WithMarineURL(server.URL), WithHTTPClient(server.Client()))
+			}
+
+			_, err := New(opts...).Forecast(test.spot)
+			if err == nil || err.Error() != test.expectError {
+				t.Errorf("expected '%s'; got '%v'", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestForecast_locator(t *testing.T) {
+	p := testServerAndProvider(t, nil, WithLocator(func(spot string) (float64, float64, error) {
+		if spot != "391" {
+			return 0, 0, errors.New("unknown spot")
+		}
+
+		return 39.277, -74.574, nil
+	}))
+
+	if _, err := p.Forecast("391"); err != nil {
+		t.Errorf("expected Forecast not to error; got '%v'", err)
+	}
+
+	if _, err := p.Forecast("392"); err == nil || !strings.Contains(err.Error(), "unknown spot") {
+		t.Errorf("expected 'unknown spot'; got '%v'", err)
+	}
+}
+
+func TestProvider_client(t *testing.T) {
+	p := testServerAndProvider(t, nil)
+	c := seaweed.NewClient("", seaweed.WithProvider(p), seaweed.WithClock(testClock{}), seaweed.WithStrictDecoding())
+
+	// Tomorrow, locally, is Sunday, October 18th. The fixtures' first hour is
+	// 8pm local time on Saturday, October 17th.
+	forecasts, err := c.Tomorrow("39.277,-74.574")
+	if err != nil {
+		t.Fatalf("expected Tomorrow not to error; got '%v'", err)
+	}
+
+	if len(forecasts) != 1 {
+		t.Errorf("expected '1' forecast; got '%d'", len(forecasts))
+	}
+}
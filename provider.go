@@ -0,0 +1,27 @@
+package seaweed
+
+// Provider fetches a spot's forecasts from a forecast data source, such as the
+// Magic Seaweed API. How a spot is identified depends on the Provider; the
+// Magic Seaweed Provider expects a Magic Seaweed spot ID.
+type Provider interface {
+	Forecast(spot string) ([]Forecast, error)
+}
+
+// ProviderFunc is a function implementing Provider.
+type ProviderFunc func(spot string) ([]Forecast, error)
+
+// Forecast implements Provider.
+func (f ProviderFunc) Forecast(spot string) ([]Forecast, error) {
+	return f(spot)
+}
+
+// WithProvider is a ClientOption configuring a *Client to fetch forecasts from
+// an alternative Provider rather than the Magic Seaweed API. The Client's
+// filtering, stale caching (see WithStaleIfError), validation (see
+// WithStrictDecoding) and archiving (see WithArchive) apply to forecasts from
+// any Provider.
+func WithProvider(p Provider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
@@ -0,0 +1,109 @@
+package seaweed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithProvider(t *testing.T) {
+	forecasts := []Forecast{{
+		Timestamp:      1442355356,
+		LocalTimestamp: 1442355356,
+		IssueTimestamp: 1442340000,
+		Swell:          Swell{Unit: "m"},
+	}, {
+		Timestamp:      1442441756,
+		LocalTimestamp: 1442441756,
+		IssueTimestamp: 1442340000,
+		Swell:          Swell{Unit: "m"},
+	}}
+
+	tests := []struct {
+		desc        string
+		provider    ProviderFunc
+		opts        []ClientOption
+		fetch       func(c *Client) ([]Forecast, error)
+		expectCount int
+		expectError string
+	}{{
+		desc: "Forecast",
+		provider: func(spot string) ([]Forecast, error) {
+			return forecasts, nil
+		},
+		fetch:       func(c *Client) ([]Forecast, error) { return c.Forecast("39.28,-74.57") },
+		expectCount: 2,
+	}, {
+		desc: "Today",
+		provider: func(spot string) ([]Forecast, error) {
+			return forecasts, nil
+		},
+		fetch:       func(c *Client) ([]Forecast, error) { return c.Today("39.28,-74.57") },
+		expectCount: 1,
+	}, {
+		desc: "when the provider fails",
+		provider: func(spot string) ([]Forecast, error) {
+			return nil, errors.New("provider unavailable")
+		},
+		fetch:       func(c *Client) ([]Forecast, error) { return c.Forecast("39.28,-74.57") },
+		expectError: "provider unavailable",
+	}, {
+		desc: "when strict and the provider's forecasts are invalid",
+		provider: func(spot string) ([]Forecast, error) {
+			return []Forecast{{Timestamp: 1442355356}}, nil
+		},
+		opts:        []ClientOption{WithStrictDecoding()},
+		fetch:       func(c *Client) ([]Forecast, error) { return c.Forecast("39.28,-74.57") },
+		expectError: "invalid forecast: [0] localTimestamp: missing",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			c := NewClient("", append([]ClientOption{WithProvider(test.provider), WithClock(testClock{})}, test.opts...)...)
+
+			got, err := test.fetch(c)
+			if test.expectError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), test.expectError) {
+					t.Errorf("expected error '%s'; got '%v'", test.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if len(got) != test.expectCount {
+				t.Errorf("expected '%d' forecasts; got '%d'", test.expectCount, len(got))
+			}
+		})
+	}
+}
+
+func TestWithProvider_staleIfError(t *testing.T) {
+	failing := false
+	provider := ProviderFunc(func(spot string) ([]Forecast, error) {
+		if failing {
+			return nil, errors.New("provider unavailable")
+		}
+
+		return []Forecast{{Timestamp: 1442355356, IssueTimestamp: 1442340000}}, nil
+	})
+
+	c := NewClient("", WithProvider(provider), WithClock(testClock{}), WithStaleIfError(time.Hour))
+	if _, err := c.Forecast("391"); err != nil {
+		t.Fatalf("expected Forecast not to error; got '%v'", err)
+	}
+
+	failing = true
+	got, err := c.Forecast("391")
+	if !IsStale(err) {
+		t.Fatalf("expected a stale error; got '%v'", err)
+	}
+
+	if len(got) != 1 {
+		t.Errorf("expected '1' stale forecast; got '%d'", len(got))
+	}
+}
@@ -0,0 +1,28 @@
+package seaweed
+
+import "strings"
+
+// MaxRating is the maximum number of stars, solid and faded, in a forecast's
+// rating.
+const MaxRating = 5
+
+// ClampRating returns n stars clamped to between 0 and MaxRating.
+func ClampRating(n int) int {
+	switch {
+	case n < 0:
+		return 0
+	case n > MaxRating:
+		return MaxRating
+	default:
+		return n
+	}
+}
+
+// Stars renders a forecast's star rating: a ★ for each solid star followed by
+// a ☆ for each faded star, together clamped to MaxRating stars.
+func Stars(f Forecast) string {
+	solid := ClampRating(int(f.SolidRating))
+	faded := ClampRating(solid+ClampRating(int(f.FadedRating))) - solid
+
+	return strings.Repeat("★", solid) + strings.Repeat("☆", faded)
+}
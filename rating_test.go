@@ -0,0 +1,42 @@
+package seaweed
+
+import "testing"
+
+func TestClampRating(t *testing.T) {
+	tests := []struct {
+		n, expect int
+	}{
+		{-1, 0},
+		{0, 0},
+		{3, 3},
+		{MaxRating, MaxRating},
+		{7, MaxRating},
+	}
+
+	for _, test := range tests {
+		if got := ClampRating(test.n); got != test.expect {
+			t.Errorf("expected ClampRating(%d) to be '%d'; got '%d'", test.n, test.expect, got)
+		}
+	}
+}
+
+func TestStars(t *testing.T) {
+	tests := []struct {
+		solid, faded FlexInt
+		expect       string
+	}{
+		{0, 0, ""},
+		{2, 1, "★★☆"},
+		{0, 3, "☆☆☆"},
+		{7, -1, "★★★★★"},
+		{4, 3, "★★★★☆"},
+		{2, -1, "★★"},
+		{0, 7, "☆☆☆☆☆"},
+	}
+
+	for _, test := range tests {
+		if got := Stars(Forecast{SolidRating: test.solid, FadedRating: test.faded}); got != test.expect {
+			t.Errorf("expected Stars(%d, %d) to be '%s'; got '%s'", test.solid, test.faded, test.expect, got)
+		}
+	}
+}
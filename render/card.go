@@ -0,0 +1,314 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mdb/seaweed"
+)
+
+const (
+	// CardWidth and CardHeight are the dimensions of a card, in pixels, at a
+	// scale of 1.
+	CardWidth  = 600
+	CardHeight = 320
+
+	cardMargin = 28
+	// accentWidth is the width of the accent bar along a card's left edge.
+	accentWidth = 8
+	// chartTop and chartBottom bound a card's bar chart.
+	chartTop    = 214
+	chartBottom = 286
+	// starRadius and starPitch size and space a card's star rating.
+	starRadius = 17
+	starPitch  = 40
+)
+
+// cardOptions configures Card.
+type cardOptions struct {
+	theme Theme
+	scale int
+}
+
+// CardOption configures Card.
+type CardOption = func(o *cardOptions)
+
+// WithCardTheme is a CardOption configuring a card's colors, which must be
+// hexadecimal, such as "#1f77b4". It defaults to Light.
+func WithCardTheme(t Theme) CardOption {
+	return func(o *cardOptions) {
+		o.theme = t
+	}
+}
+
+// WithCardScale is a CardOption multiplying a card's dimensions, such as 2
+// for a 1200x640 card. It defaults to 1.
+func WithCardScale(scale int) CardOption {
+	return func(o *cardOptions) {
+		o.scale = scale
+	}
+}
+
+// Card writes a PNG summary card of a spot's day to w; see CardImage.
+func Card(w io.Writer, spot string, forecasts []seaweed.Forecast, opts ...CardOption) error {
+	img, err := CardImage(spot, forecasts, opts...)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+// CardImage draws a summary card of a spot's day: the spot's name, the date,
+// the day's breaking height range and peak star rating, its dominant primary
+// swell, its strongest wind, and a bar chart of the breaking height range of
+// each timestep. If the forecasts span several local days, the first is
+// drawn. Text is drawn with a bundled bitmap font, such that cards are
+// identical wherever they're drawn.
+func CardImage(spot string, forecasts []seaweed.Forecast, opts ...CardOption) (*image.RGBA, error) {
+	o := &cardOptions{theme: Light, scale: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if len(forecasts) == 0 {
+		return nil, ErrNoForecasts
+	}
+
+	if o.scale < 1 {
+		return nil, fmt.Errorf("invalid card scale %d", o.scale)
+	}
+
+	p, err := newPalette(o.theme)
+	if err != nil {
+		return nil, err
+	}
+
+	day := seaweed.Summarize(forecasts)[0]
+	c := &card{
+		img:       image.NewRGBA(image.Rect(0, 0, CardWidth*o.scale, CardHeight*o.scale)),
+		scale:     o.scale,
+		palette:   p,
+		day:       day,
+		forecasts: seaweed.Filter(forecasts, seaweed.OnDay(day.Date)),
+	}
+	c.draw(spot)
+
+	return c.img, nil
+}
+
+// palette is a Theme's colors, as used by cards.
+type palette struct {
+	background, text, grid, height, rating color.RGBA
+}
+
+func newPalette(t Theme) (palette, error) {
+	var p palette
+
+	for _, c := range []struct {
+		name  string
+		value string
+		dst   *color.RGBA
+	}{
+		{"Background", t.Background, &p.background},
+		{"Text", t.Text, &p.text},
+		{"Grid", t.Grid, &p.grid},
+		{"Height", t.Height, &p.height},
+		{"Rating", t.Rating, &p.rating},
+	} {
+		rgba, err := parseHex(c.value)
+		if err != nil {
+			return palette{}, fmt.Errorf("theme %s: %w", c.name, err)
+		}
+		*c.dst = rgba
+	}
+
+	return p, nil
+}
+
+// parseHex parses a hexadecimal color of the form "#rgb" or "#rrggbb".
+func parseHex(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 || !strings.HasPrefix(s, "#") {
+		return color.RGBA{}, fmt.Errorf("invalid hexadecimal color %q", s)
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hexadecimal color %q", s)
+	}
+
+	return color.RGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 0xff}, nil
+}
+
+// card draws a summary card. Its coordinates are those of a card at a scale
+// of 1, multiplied by the scale as it draws.
+type card struct {
+	img       *image.RGBA
+	scale     int
+	palette   palette
+	day       seaweed.DaySummary
+	forecasts []seaweed.Forecast
+}
+
+func (c *card) draw(spot string) {
+	p := c.palette
+
+	c.rect(0, 0, CardWidth, CardHeight, p.background)
+	c.rect(0, 0, accentWidth, CardHeight, p.height)
+
+	width := CardWidth - 2*cardMargin
+	c.text(cardMargin, 24, fitText(spot, width*c.scale, 4*c.scale), 4, p.text)
+	c.text(cardMargin, 62, c.day.Date.Format("Monday, January 2 2006"), 2, p.text)
+
+	heights := fmt.Sprintf("%s-%s%s", round(c.day.MinBreakingHeight), round(c.day.MaxBreakingHeight), c.day.Unit)
+	c.text(cardMargin, 92, heights, 8, p.text)
+	c.stars()
+
+	swell := c.day.DominantSwell
+	c.text(cardMargin, 162, fmt.Sprintf("Primary %g%s %ds from %s", swell.Height, c.day.Unit, swell.Period, swell.From().Compass()), 2, p.text)
+	c.text(cardMargin, 186, fmt.Sprintf("Wind up to %d%s, gusts %d", c.day.MaxWindSpeed, c.day.WindUnit, c.day.MaxWindGusts), 2, p.text)
+
+	c.chart()
+}
+
+// stars draws the day's peak star rating: filled solid stars followed by
+// outlined faded stars.
+func (c *card) stars() {
+	solid := seaweed.ClampRating(c.day.PeakSolidRating)
+	faded := seaweed.ClampRating(c.day.PeakSolidRating+c.day.PeakFadedRating) - solid
+
+	x := float64(CardWidth - cardMargin - starRadius - 4*starPitch)
+	y := float64(92 + 28)
+
+	for i := 0; i < solid+faded; i++ {
+		c.star(x+float64(i*starPitch), y, i >= solid)
+	}
+}
+
+// star draws a five-pointed star centered at x, y, outlined if outline is
+// true and filled otherwise.
+func (c *card) star(x, y float64, outline bool) {
+	s := float64(c.scale)
+	outer := starPolygon(x*s, y*s, starRadius*s)
+	inner := starPolygon(x*s, y*s, (starRadius-4)*s)
+
+	r := int(math.Ceil(starRadius * s))
+	for py := int(y*s) - r; py <= int(y*s)+r; py++ {
+		for px := int(x*s) - r; px <= int(x*s)+r; px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			if !inPolygon(outer, cx, cy) || (outline && inPolygon(inner, cx, cy)) {
+				continue
+			}
+
+			c.img.SetRGBA(px, py, c.palette.rating)
+		}
+	}
+}
+
+// chart draws a bar chart of each timestep's breaking height range, shaded
+// below the range, labelled with the timesteps' local hours where they fit.
+func (c *card) chart() {
+	p := c.palette
+	s := c.scale
+
+	top := 0.0
+	for _, f := range c.forecasts {
+		top = math.Max(top, float64(f.Swell.AbsMaxBreakingHeight))
+	}
+	top = math.Max(math.Ceil(top), 1)
+
+	left, right := cardMargin*s, (CardWidth-cardMargin)*s
+	bottom, height := chartBottom*s, float64((chartBottom-chartTop)*s)
+	y := func(v float64) int {
+		return bottom - int(math.Round(v/top*height))
+	}
+
+	slot := float64(right-left) / float64(len(c.forecasts))
+	gap := int(math.Round(slot * 0.15))
+
+	for i, f := range c.forecasts {
+		x0 := left + int(math.Round(float64(i)*slot)) + gap
+		x1 := left + int(math.Round(float64(i+1)*slot)) - gap
+		if x1 <= x0 {
+			x1 = x0 + 1
+		}
+
+		min, max := y(float64(f.Swell.AbsMinBreakingHeight)), y(float64(f.Swell.AbsMaxBreakingHeight))
+		fill(c.img, image.Rect(x0, min, x1, bottom), p.grid)
+		fill(c.img, image.Rect(x0, max, x1, min), p.height)
+
+		label := localHour(f)
+		if w := textWidth(label, s); w <= x1-x0+2*gap-2*s {
+			drawText(c.img, (x0+x1-w)/2, bottom+4*s, label, s, p.text)
+		}
+	}
+
+	fill(c.img, image.Rect(left, bottom, right, bottom+s), p.text)
+}
+
+// text draws s at x, y at the font scale, in card coordinates.
+func (c *card) text(x, y int, s string, scale int, col color.RGBA) {
+	drawText(c.img, x*c.scale, y*c.scale, s, scale*c.scale, col)
+}
+
+// rect fills the rectangle from x0, y0 to x1, y1, in card coordinates.
+func (c *card) rect(x0, y0, x1, y1 int, col color.RGBA) {
+	fill(c.img, image.Rect(x0*c.scale, y0*c.scale, x1*c.scale, y1*c.scale), col)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// starPolygon returns the vertices of a five-pointed star centered at x, y,
+// pointing up, alternating between the outer radius r and the inner radius.
+func starPolygon(x, y, r float64) [][2]float64 {
+	vertices := make([][2]float64, 10)
+	for i := range vertices {
+		radius := r
+		if i%2 == 1 {
+			radius = r * 0.4
+		}
+
+		angle := -math.Pi/2 + float64(i)*math.Pi/5
+		vertices[i] = [2]float64{x + radius*math.Cos(angle), y + radius*math.Sin(angle)}
+	}
+
+	return vertices
+}
+
+// inPolygon returns true if the point x, y falls within the polygon, per the
+// even-odd rule.
+func inPolygon(vertices [][2]float64, x, y float64) bool {
+	in := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[i], vertices[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+
+	return in
+}
+
+// round formats v rounded to a whole number.
+func round(v float64) string {
+	return strconv.FormatFloat(math.Round(v), 'f', -1, 64)
+}
+
+// localHour returns the forecast's local hour, such as "07".
+func localHour(f seaweed.Forecast) string {
+	return localTime(f).Format("15")
+}
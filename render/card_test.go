@@ -0,0 +1,253 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+// goldenPNG compares the pixels of the PNG got with those of the PNG golden
+// file at path, updating the file instead when the -update flag is passed.
+// Pixels, rather than bytes, are compared, as the PNG encoder's output may
+// vary across Go releases.
+func goldenPNG(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	expected, err := decodeRGBA(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := decodeRGBA(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected.Bounds() != actual.Bounds() {
+		t.Fatalf("expected %s to be '%v'; got '%v'", path, expected.Bounds(), actual.Bounds())
+	}
+
+	if !bytes.Equal(expected.Pix, actual.Pix) {
+		t.Errorf("expected the pixels of %s; got a different image", path)
+	}
+}
+
+// decodeRGBA decodes a PNG as an *image.RGBA.
+func decodeRGBA(r io.Reader) (*image.RGBA, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return rgba, nil
+}
+
+// ratedFixture returns fixture, rated such that the day peaks at 2 solid and
+// 1 faded stars.
+func ratedFixture() []seaweed.Forecast {
+	forecasts := fixture()
+	for i := range forecasts {
+		forecasts[i].SolidRating = seaweed.FlexInt(i % 3)
+		forecasts[i].FadedRating = 1
+		forecasts[i].Swell.Components.Primary.Height = seaweed.FlexFloat(float64(i) / 2)
+		forecasts[i].Swell.Components.Primary.Direction = 45
+	}
+
+	return forecasts
+}
+
+func TestCard(t *testing.T) {
+	tests := []struct {
+		desc      string
+		spot      string
+		forecasts []seaweed.Forecast
+		opts      []CardOption
+		golden    string
+	}{{
+		desc:      "default",
+		spot:      "Pipeline",
+		forecasts: ratedFixture(),
+		golden:    "testdata/card.golden.png",
+	}, {
+		desc:      "dark with a long name",
+		spot:      "Ocean City, NJ: 8th Street through the Music Pier",
+		forecasts: ratedFixture(),
+		opts:      []CardOption{WithCardTheme(Dark)},
+		golden:    "testdata/card_dark.golden.png",
+	}, {
+		desc:      "single forecast",
+		spot:      "Pipeline",
+		forecasts: ratedFixture()[:1],
+		golden:    "testdata/card_single.golden.png",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Card(&buf, test.spot, test.forecasts, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPNG(t, test.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestCard_deterministic(t *testing.T) {
+	var a, b bytes.Buffer
+	if err := Card(&a, "Pipeline", ratedFixture()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Card(&b, "Pipeline", ratedFixture()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Error("expected identical cards from identical forecasts")
+	}
+}
+
+func TestCard_scale(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Card(&buf, "Pipeline", ratedFixture(), WithCardScale(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := img.Bounds().Size(); got.X != 2*CardWidth || got.Y != 2*CardHeight {
+		t.Errorf("expected '%dx%d'; got '%dx%d'", 2*CardWidth, 2*CardHeight, got.X, got.Y)
+	}
+
+	small, err := CardImage("Pipeline", ratedFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The accent bar, spot name and chart axis are drawn on the same pixels,
+	// each a 2x2 square at a scale of 2.
+	for _, p := range [][2]int{{4, 4}, {cardMargin + 1, 30}, {300, chartBottom}} {
+		expected := small.At(p[0], p[1])
+		if got := img.At(2*p[0]+1, 2*p[1]+1); got != expected {
+			t.Errorf("expected '%v' at %v; got '%v'", expected, p, got)
+		}
+	}
+}
+
+func TestCard_errors(t *testing.T) {
+	badTheme := Light
+	badTheme.Rating = "gold"
+
+	tests := []struct {
+		desc        string
+		forecasts   []seaweed.Forecast
+		opts        []CardOption
+		expectError string
+	}{{
+		desc:        "no forecasts",
+		expectError: ErrNoForecasts.Error(),
+	}, {
+		desc:        "invalid scale",
+		forecasts:   ratedFixture(),
+		opts:        []CardOption{WithCardScale(0)},
+		expectError: "invalid card scale 0",
+	}, {
+		desc:        "invalid theme color",
+		forecasts:   ratedFixture(),
+		opts:        []CardOption{WithCardTheme(badTheme)},
+		expectError: `theme Rating: invalid hexadecimal color "gold"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Card(&buf, "Pipeline", test.forecasts, test.opts...)
+			if err == nil || err.Error() != test.expectError {
+				t.Errorf("expected '%s'; got '%v'", test.expectError, err)
+			}
+
+			if buf.Len() != 0 {
+				t.Errorf("expected no output; got '%d' bytes", buf.Len())
+			}
+		})
+	}
+
+	if _, err := CardImage("Pipeline", nil); !errors.Is(err, ErrNoForecasts) {
+		t.Errorf("expected '%v'; got '%v'", ErrNoForecasts, err)
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		hex         string
+		expect      [3]uint8
+		expectError bool
+	}{{
+		hex:    "#1f77b4",
+		expect: [3]uint8{0x1f, 0x77, 0xb4},
+	}, {
+		hex:    "#FFF",
+		expect: [3]uint8{0xff, 0xff, 0xff},
+	}, {
+		hex:    "#0a0",
+		expect: [3]uint8{0x00, 0xaa, 0x00},
+	}, {
+		hex:         "1f77b4",
+		expectError: true,
+	}, {
+		hex:         "#1f77b",
+		expectError: true,
+	}, {
+		hex:         "#gggggg",
+		expectError: true,
+	}, {
+		hex:         "steelblue",
+		expectError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.hex, func(t *testing.T) {
+			c, err := parseHex(test.hex)
+			if test.expectError {
+				if err == nil {
+					t.Errorf("expected an error; got '%v'", c)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error; got '%v'", err)
+			}
+
+			if got := [3]uint8{c.R, c.G, c.B}; got != test.expect || c.A != 0xff {
+				t.Errorf("expected '%v'; got '%v'", test.expect, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestGlyph(t *testing.T) {
+	for r := 'a'; r <= 'z'; r++ {
+		if glyph(r) != glyphs[r-'a'+'A'] {
+			t.Errorf("expected '%c' to be drawn as '%c'", r, r-'a'+'A')
+		}
+	}
+
+	if glyph('~') != missingGlyph {
+		t.Errorf("expected '~' to be drawn as the missing glyph")
+	}
+
+	if glyph(' ') == missingGlyph {
+		t.Errorf("expected ' ' to be in the font")
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		s      string
+		scale  int
+		expect int
+	}{{
+		s:      "",
+		scale:  2,
+		expect: 0,
+	}, {
+		s:      "A",
+		scale:  1,
+		expect: 5,
+	}, {
+		s:      "5-8ft",
+		scale:  1,
+		expect: 29,
+	}, {
+		s:      "5-8ft",
+		scale:  8,
+		expect: 232,
+	}, {
+		s:      "45°",
+		scale:  2,
+		expect: 34,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			if got := textWidth(test.s, test.scale); got != test.expect {
+				t.Errorf("expected '%d'; got '%d'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestFitText(t *testing.T) {
+	tests := []struct {
+		s      string
+		width  int
+		expect string
+	}{{
+		s:      "Pipeline",
+		width:  47,
+		expect: "Pipeline",
+	}, {
+		s:      "Pipeline",
+		width:  46,
+		expect: "Pipe...",
+	}, {
+		s:      "Pipeline",
+		width:  10,
+		expect: "...",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.expect, func(t *testing.T) {
+			if got := fitText(test.s, test.width, 1); got != test.expect {
+				t.Errorf("expected '%s'; got '%s'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	black := color.RGBA{A: 0xff}
+
+	drawText(img, 2, 3, "-", 2, black)
+
+	// The hyphen is the glyph's fourth row, five font pixels wide.
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			expect := color.RGBA{}
+			if x >= 2 && x < 12 && y >= 9 && y < 11 {
+				expect = black
+			}
+
+			if got := img.RGBAAt(x, y); got != expect {
+				t.Fatalf("expected '%v' at %d,%d; got '%v'", expect, x, y, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,419 @@
+// Package render draws forecasts as SVG charts over time, suitable for
+// embedding in emails and web pages, using only the standard library.
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// ErrNoForecasts is returned when there are no forecasts to render.
+var ErrNoForecasts = errors.New("no forecasts to render")
+
+// Panel is a chart of one aspect of the forecasts.
+type Panel int
+
+const (
+	// Height charts the breaking height range as an area.
+	Height Panel = iota
+	// Period charts the primary swell period as a line.
+	Period
+	// Wind charts the wind speed and gusts as lines, with glyphs pointing in
+	// the direction the wind blows.
+	Wind
+)
+
+// Theme is the colors, as CSS color values, and font of a chart. Cards
+// require hexadecimal colors, such as "#1f77b4", and use the bundled font.
+type Theme struct {
+	Background string
+	Text       string
+	Grid       string
+	// Height is the stroke and, at HeightOpacity, fill of the breaking height
+	// range.
+	Height        string
+	HeightOpacity float64
+	Period        string
+	Wind          string
+	Gusts         string
+	// Rating is the color of star ratings, as drawn on cards.
+	Rating     string
+	FontFamily string
+}
+
+// Light is a theme of dark lines on white, and the default.
+var Light = Theme{
+	Background:    "#ffffff",
+	Text:          "#333333",
+	Grid:          "#dddddd",
+	Height:        "#1f77b4",
+	HeightOpacity: 0.35,
+	Period:        "#2ca02c",
+	Wind:          "#d62728",
+	Gusts:         "#ff9896",
+	Rating:        "#e8a317",
+	FontFamily:    "sans-serif",
+}
+
+// Dark is a theme of light lines on near black.
+var Dark = Theme{
+	Background:    "#16191d",
+	Text:          "#e6e6e6",
+	Grid:          "#3a3f45",
+	Height:        "#4fa3e0",
+	HeightOpacity: 0.4,
+	Period:        "#7bd17b",
+	Wind:          "#ff7b72",
+	Gusts:         "#a8584f",
+	Rating:        "#f5c542",
+	FontFamily:    "sans-serif",
+}
+
+const (
+	// DefaultWidth is the default width of a chart, in pixels.
+	DefaultWidth = 720
+	// DefaultPanelHeight is the default height of each panel, in pixels.
+	DefaultPanelHeight = 140
+
+	marginLeft   = 44
+	marginRight  = 16
+	marginTop    = 22
+	marginBottom = 22
+	panelGap     = 30
+	fontSize     = 11
+	// glyphRow is the distance of the wind direction glyphs below the top of
+	// the wind panel's plot area.
+	glyphRow = 8
+)
+
+// options configures SVG.
+type options struct {
+	theme       Theme
+	width       int
+	panelHeight int
+	panels      []Panel
+}
+
+// Option configures SVG.
+type Option = func(o *options)
+
+// WithTheme is an Option configuring a chart's colors and font. It defaults
+// to Light.
+func WithTheme(t Theme) Option {
+	return func(o *options) {
+		o.theme = t
+	}
+}
+
+// WithSize is an Option configuring a chart's width and the height of each of
+// its panels, in pixels. They default to DefaultWidth and DefaultPanelHeight.
+// SVG returns an error if the width leaves no room to plot within the chart's
+// margins, or if the panel height isn't positive.
+func WithSize(width, panelHeight int) Option {
+	return func(o *options) {
+		o.width = width
+		o.panelHeight = panelHeight
+	}
+}
+
+// WithPanels is an Option configuring which panels a chart draws, top to
+// bottom. It defaults to Height, Period and Wind. SVG returns an error if
+// there are no panels.
+func WithPanels(panels ...Panel) Option {
+	return func(o *options) {
+		o.panels = panels
+	}
+}
+
+// SVG writes an SVG chart of the forecasts over time to w: one panel per
+// Panel, sharing a time axis labelled with, and separated by, the forecasts'
+// local days. The forecasts are expected to be ordered by time.
+func SVG(w io.Writer, forecasts []seaweed.Forecast, opts ...Option) error {
+	o := &options{
+		theme:       Light,
+		width:       DefaultWidth,
+		panelHeight: DefaultPanelHeight,
+		panels:      []Panel{Height, Period, Wind},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if len(forecasts) == 0 {
+		return ErrNoForecasts
+	}
+
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	c := &chart{options: o, forecasts: forecasts}
+	c.draw()
+
+	_, err := w.Write(c.buf.Bytes())
+
+	return err
+}
+
+// validate returns an error if the options don't describe a drawable chart.
+func (o *options) validate() error {
+	if o.width <= marginLeft+marginRight {
+		return fmt.Errorf("invalid chart width %d", o.width)
+	}
+
+	if o.panelHeight < 1 {
+		return fmt.Errorf("invalid chart panel height %d", o.panelHeight)
+	}
+
+	if len(o.panels) == 0 {
+		return errors.New("no chart panels")
+	}
+
+	for _, p := range o.panels {
+		if p < Height || p > Wind {
+			return fmt.Errorf("invalid chart panel %d", p)
+		}
+	}
+
+	return nil
+}
+
+// chart accumulates an SVG document.
+type chart struct {
+	*options
+	forecasts []seaweed.Forecast
+	buf       bytes.Buffer
+}
+
+func (c *chart) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&c.buf, format, args...)
+}
+
+func (c *chart) draw() {
+	height := marginTop + len(c.panels)*c.panelHeight + (len(c.panels)-1)*panelGap + marginBottom
+
+	c.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s" font-size="%d">`+"\n",
+		c.width, height, c.width, height, esc(c.theme.FontFamily), fontSize)
+	c.printf(`<rect width="%d" height="%d" fill="%s"/>`+"\n", c.width, height, esc(c.theme.Background))
+
+	for i, p := range c.panels {
+		top := marginTop + i*(c.panelHeight+panelGap)
+		last := i == len(c.panels)-1
+
+		switch p {
+		case Height:
+			c.heightPanel(top, last)
+		case Period:
+			c.periodPanel(top, last)
+		case Wind:
+			c.windPanel(top, last)
+		}
+	}
+
+	c.printf("</svg>\n")
+}
+
+func (c *chart) heightPanel(top int, last bool) {
+	unit := c.forecasts[0].Swell.Unit
+	max := 0.0
+	for _, f := range c.forecasts {
+		max = math.Max(max, float64(f.Swell.AbsMaxBreakingHeight))
+	}
+
+	y := c.axes(top, "height", fmt.Sprintf("Breaking height (%s)", unit), max, last)
+
+	// The area runs along the maximum heights and back along the minimums.
+	var points []string
+	for _, f := range c.forecasts {
+		points = append(points, point(c.x(f), y(float64(f.Swell.AbsMaxBreakingHeight))))
+	}
+	for i := len(c.forecasts) - 1; i >= 0; i-- {
+		f := c.forecasts[i]
+		points = append(points, point(c.x(f), y(float64(f.Swell.AbsMinBreakingHeight))))
+	}
+
+	c.printf(`<polygon class="range" points="%s" fill="%s" fill-opacity="%s" stroke="%s" stroke-width="1.5" stroke-linejoin="round"/>`+"\n",
+		strings.Join(points, " "), esc(c.theme.Height), num(c.theme.HeightOpacity), esc(c.theme.Height))
+	c.printf("</g>\n")
+}
+
+func (c *chart) periodPanel(top int, last bool) {
+	max := 0.0
+	for _, f := range c.forecasts {
+		max = math.Max(max, float64(f.Swell.Components.Primary.Period))
+	}
+
+	y := c.axes(top, "period", "Primary swell period (s)", max, last)
+	c.line("period", c.theme.Period, "", func(f seaweed.Forecast) float64 {
+		return y(float64(f.Swell.Components.Primary.Period))
+	})
+	c.printf("</g>\n")
+}
+
+func (c *chart) windPanel(top int, last bool) {
+	unit := c.forecasts[0].Wind.Unit
+	max := 0.0
+	for _, f := range c.forecasts {
+		max = math.Max(max, math.Max(float64(f.Wind.Speed), float64(f.Wind.Gusts)))
+	}
+
+	y := c.axes(top, "wind", fmt.Sprintf("Wind and gusts (%s)", unit), max, last)
+	c.line("gusts", c.theme.Gusts, ` stroke-dasharray="4 3"`, func(f seaweed.Forecast) float64 {
+		return y(float64(f.Wind.Gusts))
+	})
+	c.line("wind", c.theme.Wind, "", func(f seaweed.Forecast) float64 {
+		return y(float64(f.Wind.Speed))
+	})
+
+	// Glyphs along the top of the plot area point in the direction the wind
+	// blows.
+	for _, f := range c.forecasts {
+		x, gy := c.x(f), float64(top+glyphRow)
+		c.printf(`<path class="direction" d="M%s l-3.5 7 l3.5 -2 l3.5 2 z" fill="%s" transform="rotate(%s %s %s)"/>`+"\n",
+			point(x, gy-4), esc(c.theme.Wind), num(float64(f.Wind.Toward())), num(x), num(gy))
+	}
+	c.printf("</g>\n")
+}
+
+// line draws a polyline through each forecast's y.
+func (c *chart) line(class, color, attrs string, y func(f seaweed.Forecast) float64) {
+	points := make([]string, len(c.forecasts))
+	for i, f := range c.forecasts {
+		points[i] = point(c.x(f), y(f))
+	}
+
+	c.printf(`<polyline class="%s" points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"%s/>`+"\n",
+		class, strings.Join(points, " "), esc(color), attrs)
+}
+
+// axes opens a panel's group, of the class, and draws its title, horizontal grid lines
+// labelled from zero to a round number at or above max, and its local day
+// separators, labelling the days beneath the panel if it's the last. It
+// returns the panel's y scale.
+func (c *chart) axes(top int, class, title string, max float64, last bool) func(v float64) float64 {
+	c.printf(`<g class="panel %s">`+"\n", class)
+	c.printf(`<text x="%d" y="%d" fill="%s" font-weight="bold">%s</text>`+"\n", marginLeft, top-8, esc(c.theme.Text), esc(title))
+
+	step := niceStep(max)
+	ceiling := step * 4
+	for ceiling-step >= max && ceiling > step {
+		ceiling -= step
+	}
+
+	bottom := float64(top + c.panelHeight)
+	y := func(v float64) float64 {
+		return bottom - v/ceiling*float64(c.panelHeight)
+	}
+
+	for v := 0.0; v <= ceiling+step/2; v += step {
+		c.printf(`<line x1="%d" y1="%s" x2="%d" y2="%s" stroke="%s" stroke-width="1"/>`+"\n",
+			marginLeft, num(y(v)), c.width-marginRight, num(y(v)), esc(c.theme.Grid))
+		c.printf(`<text x="%d" y="%s" fill="%s" text-anchor="end">%s</text>`+"\n",
+			marginLeft-6, num(y(v)+4), esc(c.theme.Text), num(v))
+	}
+
+	for i, d := range c.days() {
+		if i > 0 {
+			c.printf(`<line x1="%s" y1="%d" x2="%s" y2="%s" stroke="%s" stroke-width="1" stroke-dasharray="2 2"/>`+"\n",
+				num(d.x), top, num(d.x), num(bottom), esc(c.theme.Grid))
+		}
+
+		if last {
+			c.printf(`<text x="%s" y="%s" fill="%s">%s</text>`+"\n",
+				num(d.x+4), num(bottom+16), esc(c.theme.Text), esc(d.label))
+		}
+	}
+
+	return y
+}
+
+// day is the start of a local day on the time axis.
+type day struct {
+	x     float64
+	label string
+}
+
+// days returns the start of each local day the forecasts span: the first
+// forecast's position, followed by each local midnight between forecasts.
+func (c *chart) days() []day {
+	first := c.forecasts[0]
+	days := []day{{c.x(first), localTime(first).Format("Mon Jan 2")}}
+
+	for i := 1; i < len(c.forecasts); i++ {
+		prev, f := localTime(c.forecasts[i-1]), localTime(c.forecasts[i])
+		if prev.YearDay() == f.YearDay() && prev.Year() == f.Year() {
+			continue
+		}
+
+		// Local midnight, expressed as a Timestamp per the forecast's offset.
+		midnight := time.Date(f.Year(), f.Month(), f.Day(), 0, 0, 0, 0, time.UTC).Unix()
+		offset := int64(c.forecasts[i].LocalTimestamp - c.forecasts[i].Timestamp)
+		days = append(days, day{c.xAt(midnight - offset), f.Format("Mon Jan 2")})
+	}
+
+	return days
+}
+
+// x returns the forecast's position on the time axis.
+func (c *chart) x(f seaweed.Forecast) float64 {
+	return c.xAt(int64(f.Timestamp))
+}
+
+func (c *chart) xAt(ts int64) float64 {
+	left, right := float64(marginLeft), float64(c.width-marginRight)
+
+	first, last := int64(c.forecasts[0].Timestamp), int64(c.forecasts[len(c.forecasts)-1].Timestamp)
+	if last == first {
+		return (left + right) / 2
+	}
+
+	return left + float64(ts-first)/float64(last-first)*(right-left)
+}
+
+// niceStep returns a round grid step dividing max into at most four steps.
+func niceStep(max float64) float64 {
+	if max <= 0 {
+		return 1
+	}
+
+	raw := max / 4
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if step := m * magnitude; step >= raw {
+			return step
+		}
+	}
+
+	return 10 * magnitude
+}
+
+func localTime(f seaweed.Forecast) time.Time {
+	return time.Unix(int64(f.LocalTimestamp), 0).UTC()
+}
+
+// num formats v with at most two decimal places, and without trailing zeros,
+// such that output is stable.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func point(x, y float64) string {
+	return num(x) + "," + num(y)
+}
+
+// esc escapes s for use in SVG text and attribute values.
+func esc(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
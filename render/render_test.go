@@ -0,0 +1,200 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// golden compares got with the contents of the golden file at path, updating
+// the file instead when the -update flag is passed.
+func golden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expected, got) {
+		t.Errorf("expected %s:\n%s\ngot:\n%s", path, expected, got)
+	}
+}
+
+// fixture returns 3-hourly forecasts spanning two local days, five hours
+// behind UTC.
+func fixture() []seaweed.Forecast {
+	heights := []float64{2, 2.5, 3, 4, 5, 5.5, 5, 4.5, 4, 3.5, 3}
+	periods := []int{8, 8, 9, 10, 11, 12, 12, 11, 11, 10, 9}
+	winds := []int{5, 6, 8, 12, 15, 12, 10, 7, 6, 8, 10}
+	towards := []int64{225, 225, 250, 270, 290, 315, 0, 20, 45, 90, 135}
+
+	var forecasts []seaweed.Forecast
+	for i := range heights {
+		local := int64(1677913200) + int64(i)*3*3600 // from Sat 2023-03-04 07:00
+
+		f := seaweed.Forecast{
+			Timestamp:      seaweed.FlexInt64(local + 5*3600),
+			LocalTimestamp: seaweed.FlexInt64(local),
+		}
+		f.Swell.Unit = "ft"
+		f.Swell.AbsMinBreakingHeight = seaweed.FlexFloat(heights[i] - 1)
+		f.Swell.AbsMaxBreakingHeight = seaweed.FlexFloat(heights[i])
+		f.Swell.Components.Primary.Period = seaweed.FlexInt(periods[i])
+		f.Wind = seaweed.Wind{
+			Speed:     seaweed.FlexInt(winds[i]),
+			Gusts:     seaweed.FlexInt64(winds[i] + 6),
+			Direction: seaweed.FlexInt64(towards[i]),
+			Unit:      "mph",
+		}
+
+		forecasts = append(forecasts, f)
+	}
+
+	return forecasts
+}
+
+func TestSVG(t *testing.T) {
+	tests := []struct {
+		desc      string
+		forecasts []seaweed.Forecast
+		opts      []Option
+		golden    string
+	}{{
+		desc:      "default",
+		forecasts: fixture(),
+		golden:    "testdata/chart.golden.svg",
+	}, {
+		desc:      "dark wind panel",
+		forecasts: fixture(),
+		opts:      []Option{WithTheme(Dark), WithSize(480, 100), WithPanels(Wind)},
+		golden:    "testdata/wind_dark.golden.svg",
+	}, {
+		desc:      "single forecast",
+		forecasts: fixture()[:1],
+		opts:      []Option{WithPanels(Height, Period)},
+		golden:    "testdata/single.golden.svg",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := SVG(&buf, test.forecasts, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			golden(t, test.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestSVG_noForecasts(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SVG(&buf, nil); !errors.Is(err, ErrNoForecasts) {
+		t.Errorf("expected '%v'; got '%v'", ErrNoForecasts, err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output; got '%s'", buf.String())
+	}
+}
+
+func TestSVG_invalidOptions(t *testing.T) {
+	tests := []struct {
+		desc        string
+		opts        []Option
+		expectError string
+	}{{
+		desc:        "zero width",
+		opts:        []Option{WithSize(0, DefaultPanelHeight)},
+		expectError: "invalid chart width 0",
+	}, {
+		desc:        "width within the margins",
+		opts:        []Option{WithSize(marginLeft+marginRight, DefaultPanelHeight)},
+		expectError: "invalid chart width 60",
+	}, {
+		desc:        "negative panel height",
+		opts:        []Option{WithSize(DefaultWidth, -1)},
+		expectError: "invalid chart panel height -1",
+	}, {
+		desc:        "no panels",
+		opts:        []Option{WithPanels()},
+		expectError: "no chart panels",
+	}, {
+		desc:        "unknown panel",
+		opts:        []Option{WithPanels(Height, Panel(7))},
+		expectError: "invalid chart panel 7",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := SVG(&buf, fixture(), test.opts...)
+			if err == nil || err.Error() != test.expectError {
+				t.Errorf("expected '%s'; got '%v'", test.expectError, err)
+			}
+
+			if buf.Len() != 0 {
+				t.Errorf("expected no output; got '%s'", buf.String())
+			}
+		})
+	}
+}
+
+func TestSVG_escaping(t *testing.T) {
+	theme := Light
+	theme.FontFamily = `"Helvetica Neue", <sans-serif>`
+
+	forecasts := fixture()
+	forecasts[0].Swell.Unit = "<ft>"
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, forecasts, WithTheme(theme)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expect := range []string{
+		`font-family="&#34;Helvetica Neue&#34;, &lt;sans-serif&gt;"`,
+		"Breaking height (&lt;ft&gt;)",
+	} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("expected output to contain '%s'", expect)
+		}
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		max    float64
+		expect float64
+	}{
+		{0, 1},
+		{-3, 1},
+		{4, 1},
+		{5.5, 2},
+		{8, 2},
+		{10, 2.5},
+		{12, 5},
+		{21, 10},
+		{0.3, 0.1},
+	}
+
+	for _, test := range tests {
+		if got := niceStep(test.max); got != test.expect {
+			t.Errorf("expected the step for '%g' to be '%g'; got '%g'", test.max, test.expect, got)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package seaweed
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // APIError represents a Seaweed API error response body.
 //
@@ -10,6 +13,24 @@ type APIError struct {
 	ErrorResponse ErrorResponse `json:"error_response"`
 }
 
+// Error returns the API's error message.
+func (e *APIError) Error() string {
+	return e.ErrorResponse.ErrorMsg
+}
+
+// HTTPError is returned when the Magic Seaweed API responds with an
+// unexpected HTTP status code.
+type HTTPError struct {
+	// URL is the requested URL, with the API key redacted.
+	URL string
+	// StatusCode is the response's HTTP status code.
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("GET %s returned HTTP status code %d", e.URL, e.StatusCode)
+}
+
 // ErrorResponse represents a Seaweed API error response.
 type ErrorResponse struct {
 	Code     int    `json:"code"`
@@ -18,11 +39,11 @@ type ErrorResponse struct {
 
 // Forecast represents a Seaweed API forecast.
 type Forecast struct {
-	Timestamp      int64     `json:"timestamp"`
-	LocalTimestamp int64     `json:"localTimestamp"`
-	IssueTimestamp int64     `json:"issueTimestamp"`
-	FadedRating    int       `json:"FadedRating"`
-	SolidRating    int       `json:"SolidRating"`
+	Timestamp      FlexInt64 `json:"timestamp"`
+	LocalTimestamp FlexInt64 `json:"localTimestamp"`
+	IssueTimestamp FlexInt64 `json:"issueTimestamp"`
+	FadedRating    FlexInt   `json:"FadedRating"`
+	SolidRating    FlexInt   `json:"SolidRating"`
 	Swell          Swell     `json:"swell"`
 	Wind           Wind      `json:"wind"`
 	Condition      Condition `json:"condition"`
@@ -31,7 +52,7 @@ type Forecast struct {
 
 // IsWeekend returns true if a forecast pertains to a Saturday or a Sunday.
 func (f Forecast) IsWeekend() bool {
-	day := time.Unix(f.LocalTimestamp, 0).UTC().Weekday().String()
+	day := time.Unix(int64(f.LocalTimestamp), 0).UTC().Weekday().String()
 
 	if day == "Saturday" || day == "Sunday" {
 		return true
@@ -42,55 +63,58 @@ func (f Forecast) IsWeekend() bool {
 
 // IsDay returns true if a forecast pertains to the day it's passed.
 func (f Forecast) IsDay(t time.Time) bool {
-	day := time.Unix(f.LocalTimestamp, 0).UTC()
+	day := time.Unix(int64(f.LocalTimestamp), 0).UTC()
 
 	return day.Day() == t.Day() && day.Month() == t.Month() && day.Year() == t.Year()
 }
 
 // Swell represents a Seaweed API forecast's swell.
 type Swell struct {
-	MinBreakingHeight    int        `json:"minBreakingHeight"`
-	AbsMinBreakingHeight float64    `json:"absMinBreakingHeight"`
-	MaxBreakingHeight    int        `json:"maxBreakingHeight"`
-	AbsMaxBreakingHeight float64    `json:"absMaxBreakingHeight"`
-	Probability          int        `json:"probability"`
+	MinBreakingHeight    FlexInt    `json:"minBreakingHeight"`
+	AbsMinBreakingHeight FlexFloat  `json:"absMinBreakingHeight"`
+	MaxBreakingHeight    FlexInt    `json:"maxBreakingHeight"`
+	AbsMaxBreakingHeight FlexFloat  `json:"absMaxBreakingHeight"`
+	Probability          FlexInt    `json:"probability"`
 	Unit                 string     `json:"unit"`
 	Components           Components `json:"components"`
 }
 
 // Components represents a Seaweed API forecast's swell's components.
+//
+// The API omits secondary and tertiary swell components when there are none;
+// Secondary and Tertiary are nil in such instances.
 type Components struct {
-	Combined  Component `json:"combined"`
-	Primary   Component `json:"primary"`
-	Secondary Component `json:"secondary"`
-	Tertiary  Component `json:"tertiary"`
+	Combined  Component  `json:"combined"`
+	Primary   Component  `json:"primary"`
+	Secondary *Component `json:"secondary,omitempty"`
+	Tertiary  *Component `json:"tertiary,omitempty"`
 }
 
 // Component represents a Seaweed API forecast's swell component.
 type Component struct {
-	Height           float64 `json:"height"`
-	Period           int     `json:"period"`
-	Direction        float64 `json:"direction"`
-	CompassDirection string  `json:"compassDirection"`
+	Height           FlexFloat `json:"height"`
+	Period           FlexInt   `json:"period"`
+	Direction        FlexFloat `json:"direction"`
+	CompassDirection string    `json:"compassDirection"`
 }
 
 // Wind represents a Seaweed API forecast's wind.
 type Wind struct {
-	Speed            int    `json:"speed"`
-	Direction        int64  `json:"direction"`
-	CompassDirection string `json:"compassDirection"`
-	Chill            int64  `json:"chill"`
-	Gusts            int64  `json:"gusts"`
-	Unit             string `json:"unit"`
+	Speed            FlexInt   `json:"speed"`
+	Direction        FlexInt64 `json:"direction"`
+	CompassDirection string    `json:"compassDirection"`
+	Chill            FlexInt64 `json:"chill"`
+	Gusts            FlexInt64 `json:"gusts"`
+	Unit             string    `json:"unit"`
 }
 
 // Condition represents a Seaweed API forecast's condition.
 type Condition struct {
-	Pressure     int64  `json:"pressure"`
-	Temperature  int64  `json:"temperature"`
-	Weather      string `json:"weather"`
-	Unit         string `json:"unit"`
-	UnitPressure string `json:"unitPressure"`
+	Pressure     FlexInt64  `json:"pressure"`
+	Temperature  FlexInt64  `json:"temperature"`
+	Weather      FlexString `json:"weather"`
+	Unit         string     `json:"unit"`
+	UnitPressure string     `json:"unitPressure"`
 }
 
 // Charts represents a Seaweed API forecast's charts.
@@ -99,4 +123,5 @@ type Charts struct {
 	Period   string `json:"period"`
 	Wind     string `json:"wind"`
 	Pressure string `json:"pressure"`
+	SST      string `json:"sst"`
 }
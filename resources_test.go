@@ -34,7 +34,7 @@ func TestForecast_IsWeekend(t *testing.T) {
 func TestForecast_IsDay(t *testing.T) {
 	ts := int64(1677973254)
 	f := Forecast{
-		LocalTimestamp: ts,
+		LocalTimestamp: FlexInt64(ts),
 	}
 
 	today := time.Unix(ts, 0)
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/mdb/seaweed"
+)
+
+// apiErrorStatuses maps Magic Seaweed API error_response codes to the HTTP
+// status with which the Server responds. Unlisted codes, which report faults
+// of the API or of the Server's configuration, map to 502 Bad Gateway.
+var apiErrorStatuses = map[int]int{
+	// 115 reports that the API key could not be authenticated. This is a
+	// misconfiguration of the Server rather than a fault of its client.
+	115: http.StatusBadGateway,
+	// 501 reports that the request's parameters failed the API's validation.
+	// The Server validates the units it forwards, so the parameter rejected
+	// is the spot ID: the requested spot doesn't exist.
+	501: http.StatusNotFound,
+}
+
+// errorStatus returns the HTTP status, message, and Magic Seaweed API error
+// code with which to respond to err.
+//
+// Only Magic Seaweed API error messages are surfaced verbatim; other errors,
+// such as a *url.Error, may include the API key and are reported generically.
+func errorStatus(err error) (int, string, int) {
+	var apiErr *seaweed.APIError
+	if errors.As(err, &apiErr) {
+		status, ok := apiErrorStatuses[apiErr.ErrorResponse.Code]
+		if !ok {
+			status = http.StatusBadGateway
+		}
+
+		return status, apiErr.ErrorResponse.ErrorMsg, apiErr.ErrorResponse.Code
+	}
+
+	var httpErr *seaweed.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusNotFound:
+			return http.StatusNotFound, "spot not found", 0
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return http.StatusServiceUnavailable, "upstream API rate limit exceeded", 0
+		default:
+			return http.StatusBadGateway, "upstream API error", 0
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return http.StatusGatewayTimeout, "upstream API timed out", 0
+	}
+
+	return http.StatusBadGateway, "upstream API error", 0
+}
+
+// errorBody is the JSON body of an error response.
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Message string `json:"message"`
+	Code    int    `json:"code,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string, code int) {
+	writeJSON(w, status, errorBody{errorDetail{Message: msg, Code: code}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/metrics"
+)
+
+// serverMetrics are the metrics a Server exposes at /metrics.
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	requests      *metrics.CounterVec
+	errors        *metrics.CounterVec
+	latency       *metrics.HistogramVec
+	cacheHits     *metrics.CounterVec
+	cacheMisses   *metrics.CounterVec
+	cacheHitRatio *metrics.GaugeVec
+
+	minBreakingHeight *metrics.GaugeVec
+	maxBreakingHeight *metrics.GaugeVec
+	primaryPeriod     *metrics.GaugeVec
+	windSpeed         *metrics.GaugeVec
+	windGusts         *metrics.GaugeVec
+	solidRating       *metrics.GaugeVec
+	fadedRating       *metrics.GaugeVec
+}
+
+func newServerMetrics(s *Server) *serverMetrics {
+	r := metrics.NewRegistry()
+	spotLabels := []string{"spot", "units"}
+
+	m := &serverMetrics{
+		registry: r,
+
+		requests:      r.NewCounterVec("seaweed_client_requests_total", "Magic Seaweed API forecast requests.", "units"),
+		errors:        r.NewCounterVec("seaweed_client_errors_total", "Failed Magic Seaweed API forecast requests by API error code, HTTP status, or transport failure.", "code"),
+		latency:       r.NewHistogramVec("seaweed_client_request_duration_seconds", "Magic Seaweed API forecast request latency.", nil),
+		cacheHits:     r.NewCounterVec("seaweed_cache_hits_total", "Forecast requests served from the cache."),
+		cacheMisses:   r.NewCounterVec("seaweed_cache_misses_total", "Forecast requests not served from the cache."),
+		cacheHitRatio: r.NewGaugeVec("seaweed_cache_hit_ratio", "Ratio of forecast requests served from the cache."),
+
+		minBreakingHeight: r.NewGaugeVec("seaweed_swell_min_breaking_height", "Minimum breaking swell height at the nearest forecast timestep.", spotLabels...),
+		maxBreakingHeight: r.NewGaugeVec("seaweed_swell_max_breaking_height", "Maximum breaking swell height at the nearest forecast timestep.", spotLabels...),
+		primaryPeriod:     r.NewGaugeVec("seaweed_swell_primary_period_seconds", "Primary swell period at the nearest forecast timestep.", spotLabels...),
+		windSpeed:         r.NewGaugeVec("seaweed_wind_speed", "Wind speed at the nearest forecast timestep.", spotLabels...),
+		windGusts:         r.NewGaugeVec("seaweed_wind_gusts", "Wind gust speed at the nearest forecast timestep.", spotLabels...),
+		solidRating:       r.NewGaugeVec("seaweed_solid_rating", "Solid star rating at the nearest forecast timestep.", spotLabels...),
+		fadedRating:       r.NewGaugeVec("seaweed_faded_rating", "Faded star rating at the nearest forecast timestep.", spotLabels...),
+	}
+
+	r.OnCollect(func() {
+		m.collectCache()
+		m.collectSpots(s)
+	})
+
+	return m
+}
+
+// observeCache records a cache hit or miss.
+func (m *serverMetrics) observeCache(hit bool) {
+	if hit {
+		m.cacheHits.Inc()
+		return
+	}
+
+	m.cacheMisses.Inc()
+}
+
+// observeRequest records a Magic Seaweed API request and its outcome.
+func (m *serverMetrics) observeRequest(units string, d time.Duration, err error) {
+	if units == "" {
+		units = "default"
+	}
+
+	m.requests.Inc(units)
+	m.latency.Observe(d.Seconds())
+
+	if err != nil {
+		m.errors.Inc(errorCode(err))
+	}
+}
+
+func (m *serverMetrics) collectCache() {
+	hits, misses := m.cacheHits.Value(), m.cacheMisses.Value()
+	if hits+misses > 0 {
+		m.cacheHitRatio.Set(hits / (hits + misses))
+	}
+}
+
+// collectSpots sets the condition gauges of each spot the Server has recently
+// fetched, whether or not its forecasts are cached, from the forecast timestep
+// nearest the current time.
+func (m *serverMetrics) collectSpots(s *Server) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	latest := make(map[cacheKey][]seaweed.Forecast, s.latest.Len())
+	s.latest.Range(func(k cacheKey, forecasts []seaweed.Forecast) {
+		latest[k] = forecasts
+	})
+	s.mu.Unlock()
+
+	gauges := []*metrics.GaugeVec{
+		m.minBreakingHeight, m.maxBreakingHeight, m.primaryPeriod,
+		m.windSpeed, m.windGusts, m.solidRating, m.fadedRating,
+	}
+	for _, g := range gauges {
+		g.Reset()
+	}
+
+	for k, forecasts := range latest {
+		f, ok := nearest(forecasts, now)
+		if !ok {
+			continue
+		}
+
+		units := k.units
+		if units == "" {
+			units = "default"
+		}
+
+		m.minBreakingHeight.Set(float64(f.Swell.AbsMinBreakingHeight), k.spot, units)
+		m.maxBreakingHeight.Set(float64(f.Swell.AbsMaxBreakingHeight), k.spot, units)
+		m.primaryPeriod.Set(float64(f.Swell.Components.Primary.Period), k.spot, units)
+		m.windSpeed.Set(float64(f.Wind.Speed), k.spot, units)
+		m.windGusts.Set(float64(f.Wind.Gusts), k.spot, units)
+		m.solidRating.Set(float64(f.SolidRating), k.spot, units)
+		m.fadedRating.Set(float64(f.FadedRating), k.spot, units)
+	}
+}
+
+// nearest returns the forecast whose timestamp is nearest t.
+func nearest(forecasts []seaweed.Forecast, t time.Time) (seaweed.Forecast, bool) {
+	var best seaweed.Forecast
+	bestDelta := math.Inf(1)
+
+	for _, f := range forecasts {
+		delta := math.Abs(float64(int64(f.Timestamp) - t.Unix()))
+		if delta < bestDelta {
+			best, bestDelta = f, delta
+		}
+	}
+
+	return best, len(forecasts) > 0
+}
+
+// errorCode returns the seaweed_client_errors_total code label for err: the
+// Magic Seaweed API's error_response code, http_<status> for unexpected HTTP
+// statuses, or "other".
+func errorCode(err error) string {
+	var apiErr *seaweed.APIError
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.ErrorResponse.Code)
+	}
+
+	var httpErr *seaweed.HTTPError
+	if errors.As(err, &httpErr) {
+		return "http_" + strconv.Itoa(httpErr.StatusCode)
+	}
+
+	return "other"
+}
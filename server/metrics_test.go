@@ -0,0 +1,97 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServer_metrics(t *testing.T) {
+	u := &upstream{code: 200, body: resp}
+	api, srv := testServer(u)
+	defer api.Close()
+	defer srv.Close()
+
+	for _, path := range []string{"/spots/391/forecast", "/spots/391/today", "/spots/391/forecast?units=eu"} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	u.body = errorResp
+	res, err := http.Get(srv.URL + "/spots/392/forecast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	res, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`seaweed_cache_hit_ratio 0.25`,
+		`seaweed_cache_hits_total 1`,
+		`seaweed_cache_misses_total 3`,
+		`seaweed_client_errors_total{code="115"} 1`,
+		`seaweed_client_request_duration_seconds_count 3`,
+		`seaweed_client_requests_total{units="default"} 2`,
+		`seaweed_client_requests_total{units="eu"} 1`,
+		`seaweed_faded_rating{spot="391",units="default"} 3`,
+		`seaweed_solid_rating{spot="391",units="eu"} 0`,
+		`seaweed_swell_max_breaking_height{spot="391",units="default"} 7.63`,
+		`seaweed_swell_min_breaking_height{spot="391",units="default"} 4.88`,
+		`seaweed_swell_primary_period_seconds{spot="391",units="default"} 10`,
+		`seaweed_wind_gusts{spot="391",units="default"} 27`,
+		`seaweed_wind_speed{spot="391",units="default"} 13`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("expected metrics to include '%s'; got:\n%s", line, body)
+		}
+	}
+
+	if strings.Contains(string(body), `spot="392"`) {
+		t.Error("expected no condition gauges for a spot whose forecast failed")
+	}
+}
+
+func TestServer_metricsWithoutCache(t *testing.T) {
+	u := &upstream{code: 200, body: resp}
+	api, srv := testServer(u, WithCacheTTL(0))
+	defer api.Close()
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/spots/391/forecast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	res, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `seaweed_wind_speed{spot="391",units="default"} 13`
+	if !strings.Contains(string(body), expected+"\n") {
+		t.Errorf("expected metrics to include '%s' with caching disabled; got:\n%s", expected, body)
+	}
+}
@@ -0,0 +1,353 @@
+// Package server provides an HTTP server exposing a JSON REST API over a
+// seaweed.Client, such that browsers may fetch Magic Seaweed forecasts without
+// ever holding the Magic Seaweed API key.
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/mdb/seaweed/internal/lru"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCacheTTL is the default duration for which a Server caches a
+	// spot's forecasts.
+	DefaultCacheTTL = 30 * time.Minute
+	// DefaultMaxCachedSpots is the default maximum number of spots' forecasts
+	// a Server caches.
+	DefaultMaxCachedSpots = 1000
+)
+
+// units are the supported values of the units query parameter. The empty
+// string requests the Magic Seaweed API's default.
+var units = map[string]bool{
+	"":   true,
+	"us": true,
+	"uk": true,
+	"eu": true,
+}
+
+// Server is an http.Handler serving the following JSON endpoints:
+//
+//	GET /spots/{id}/forecast
+//	GET /spots/{id}/today
+//	GET /spots/{id}/tomorrow
+//	GET /spots/{id}/weekend
+//	GET /metrics
+//
+// Each accepts optional units ("us", "uk", or "eu"), from, and to query
+// parameters; from and to are RFC3339 times bounding the returned forecasts'
+// timestamps. /metrics serves the Server's metrics in the Prometheus text
+// exposition format.
+type Server struct {
+	// apiKey is the Magic Seaweed API key with which the Server's clients are
+	// configured. It is never included in responses.
+	apiKey string
+	// clientOpts are the seaweed.ClientOptions with which the Server's clients
+	// are configured.
+	clientOpts []seaweed.ClientOption
+	// ttl is the duration for which spots' forecasts are cached.
+	ttl time.Duration
+	// maxCachedSpots is the maximum number of entries cache holds.
+	maxCachedSpots int
+	// clock reports the current time.
+	clock seaweed.Clock
+	// Logger is a *logrus.Logger.
+	Logger *logrus.Logger
+
+	// mu guards clients, cache and latest.
+	mu sync.Mutex
+	// clients holds a *seaweed.Client for each requested unit system, which
+	// units validates, so it holds at most one per supported unit system.
+	clients map[string]*seaweed.Client
+	// cache holds unexpired forecasts keyed by unit system and spot ID, for
+	// the most recently requested spots.
+	cache *lru.Cache[cacheKey, cacheEntry]
+	// latest holds the last successfully fetched forecasts keyed by unit
+	// system and spot ID, for the most recently fetched spots, regardless of
+	// whether they're cached.
+	latest *lru.Cache[cacheKey, []seaweed.Forecast]
+	// metrics are the metrics served at /metrics.
+	metrics *serverMetrics
+}
+
+type cacheKey struct {
+	units string
+	spot  string
+}
+
+type cacheEntry struct {
+	forecasts []seaweed.Forecast
+	expires   time.Time
+}
+
+// Option configures one or more Server fields.
+type Option = func(s *Server)
+
+// WithClientOptions is an Option to configure the seaweed.ClientOptions with
+// which a *Server's clients are created.
+func WithClientOptions(opts ...seaweed.ClientOption) Option {
+	return func(s *Server) {
+		s.clientOpts = append(s.clientOpts, opts...)
+	}
+}
+
+// WithCacheTTL is an Option to configure how long a *Server caches a spot's
+// forecasts. A ttl of 0 disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.ttl = ttl
+	}
+}
+
+// WithMaxCachedSpots is an Option to configure the maximum number of spots'
+// forecasts a *Server caches, counting each unit system separately, evicting
+// the least recently requested beyond it. It also bounds the number of spots cached by
+// each of the *Server's clients. It defaults to DefaultMaxCachedSpots.
+func WithMaxCachedSpots(n int) Option {
+	return func(s *Server) {
+		s.maxCachedSpots = n
+	}
+}
+
+// WithClock is an Option to configure a *Server's clock.
+func WithClock(clock seaweed.Clock) Option {
+	return func(s *Server) {
+		s.clock = clock
+	}
+}
+
+// WithLogger is an Option to configure a *Server's Logger.
+func WithLogger(l *logrus.Logger) Option {
+	return func(s *Server) {
+		s.Logger = l
+	}
+}
+
+// New takes a Magic Seaweed API key and returns a *Server.
+func New(apiKey string, opts ...Option) *Server {
+	s := &Server{
+		apiKey:         apiKey,
+		ttl:            DefaultCacheTTL,
+		maxCachedSpots: DefaultMaxCachedSpots,
+		clock:          seaweed.RealClock{},
+		Logger:         logrus.New(),
+		clients:        map[string]*seaweed.Client{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.cache = lru.New[cacheKey, cacheEntry](s.maxCachedSpots)
+	s.latest = lru.New[cacheKey, []seaweed.Forecast](s.maxCachedSpots)
+
+	s.metrics = newServerMetrics(s)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/metrics" {
+		s.metrics.registry.ServeHTTP(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "spots" {
+		writeError(w, http.StatusNotFound, "not found", 0)
+		return
+	}
+
+	spot, endpoint := parts[1], parts[2]
+
+	filter, ok := s.filter(endpoint)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found", 0)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", 0)
+		return
+	}
+
+	if !validSpot(spot) {
+		writeError(w, http.StatusBadRequest, "spot ID must be numeric", 0)
+		return
+	}
+
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), 0)
+		return
+	}
+
+	forecasts, expires, err := s.forecast(q.units, spot)
+	if err != nil && !seaweed.IsStale(err) {
+		s.Logger.WithFields(logrus.Fields{"spot": spot, "units": q.units}).Errorf("fetching forecast: %s", err)
+		status, msg, code := errorStatus(err)
+		writeError(w, status, msg, code)
+		return
+	}
+
+	if err != nil {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+
+	matching := seaweed.Filter(forecasts, filter, seaweed.Between(q.from, q.to))
+	if matching == nil {
+		matching = []seaweed.Forecast{}
+	}
+
+	if ttl := expires.Sub(s.clock.Now()); ttl > 0 {
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(ttl.Seconds())))
+	}
+
+	writeJSON(w, http.StatusOK, matching)
+}
+
+// filter returns the predicate selecting the forecasts served by endpoint.
+func (s *Server) filter(endpoint string) (seaweed.Predicate, bool) {
+	now := s.clock.Now().UTC()
+
+	switch endpoint {
+	case "forecast":
+		return seaweed.And(), true
+	case "today":
+		return seaweed.OnDay(now), true
+	case "tomorrow":
+		return seaweed.OnDay(now.AddDate(0, 0, 1)), true
+	case "weekend":
+		return seaweed.OnWeekend(), true
+	default:
+		return nil, false
+	}
+}
+
+// forecast returns the spot's forecasts in the given unit system, from the
+// cache if possible, and the time at which they expire from the cache, which
+// is zero if they aren't cached.
+func (s *Server) forecast(units, spot string) ([]seaweed.Forecast, time.Time, error) {
+	key := cacheKey{units, spot}
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache.Get(key)
+	if ok && !now.Before(entry.expires) {
+		s.cache.Remove(key)
+		ok = false
+	}
+	client := s.client(units)
+	s.mu.Unlock()
+
+	if ok {
+		s.metrics.observeCache(true)
+		return entry.forecasts, entry.expires, nil
+	}
+
+	s.metrics.observeCache(false)
+
+	start := time.Now()
+	forecasts, err := client.Forecast(spot)
+	s.metrics.observeRequest(units, time.Since(start), err)
+	if err != nil {
+		return forecasts, time.Time{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latest.Add(key, forecasts)
+
+	if s.ttl <= 0 {
+		return forecasts, time.Time{}, nil
+	}
+
+	expires := now.Add(s.ttl)
+	s.cache.RemoveFunc(func(_ cacheKey, e cacheEntry) bool {
+		return !now.Before(e.expires)
+	})
+	s.cache.Add(key, cacheEntry{forecasts: forecasts, expires: expires})
+
+	return forecasts, expires, nil
+}
+
+// client returns the *seaweed.Client for the unit system, creating it if
+// necessary. s.mu must be held.
+func (s *Server) client(units string) *seaweed.Client {
+	if c, ok := s.clients[units]; ok {
+		return c
+	}
+
+	opts := append([]seaweed.ClientOption{seaweed.WithLogger(s.Logger), seaweed.WithMaxCachedSpots(s.maxCachedSpots)}, s.clientOpts...)
+	if units != "" {
+		opts = append(opts, seaweed.WithUnits(units))
+	}
+
+	c := seaweed.NewClient(s.apiKey, opts...)
+	s.clients[units] = c
+
+	return c
+}
+
+// query holds a request's parsed query parameters.
+type query struct {
+	units    string
+	from, to time.Time
+}
+
+func parseQuery(r *http.Request) (query, error) {
+	var q query
+	v := r.URL.Query()
+
+	q.units = v.Get("units")
+	if !units[q.units] {
+		return q, errors.New("units must be one of us, uk, or eu")
+	}
+
+	for _, param := range []struct {
+		name string
+		t    *time.Time
+	}{{"from", &q.from}, {"to", &q.to}} {
+		raw := v.Get(param.name)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return q, errors.New(param.name + " must be an RFC3339 time")
+		}
+		*param.t = t
+	}
+
+	if !q.from.IsZero() && !q.to.IsZero() && q.to.Before(q.from) {
+		return q, errors.New("to must not precede from")
+	}
+
+	return q, nil
+}
+
+func validSpot(spot string) bool {
+	if spot == "" {
+		return false
+	}
+
+	for _, r := range spot {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
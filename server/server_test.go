@@ -0,0 +1,367 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	resp      string
+	errorResp string
+)
+
+func TestMain(m *testing.M) {
+	content, err := ioutil.ReadFile("../testdata/response.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp = string(content)
+
+	errContent, err := ioutil.ReadFile("../testdata/error.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	errorResp = string(errContent)
+
+	exitVal := m.Run()
+	os.Exit(exitVal)
+}
+
+type testClock struct{}
+
+func (testClock) Now() time.Time {
+	return time.Unix(1442355356, 0).UTC()
+}
+
+// upstream is a fake Magic Seaweed API.
+type upstream struct {
+	code     int
+	body     string
+	requests atomic.Int32
+	units    atomic.Value
+	key      atomic.Value
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.requests.Add(1)
+	u.units.Store(r.URL.Query().Get("units"))
+	u.key.Store(strings.Split(r.URL.Path, "/")[2])
+	w.WriteHeader(u.code)
+	fmt.Fprint(w, u.body)
+}
+
+func testServer(u *upstream, opts ...Option) (*httptest.Server, *httptest.Server) {
+	api := httptest.NewTLSServer(u)
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s := New("secretKey", append([]Option{
+		WithClock(testClock{}),
+		WithLogger(logger),
+		WithClientOptions(
+			seaweed.WithBaseURL(api.URL),
+			seaweed.WithHTTPClient(api.Client()),
+			seaweed.WithClock(testClock{}),
+		),
+	}, opts...)...)
+
+	return api, httptest.NewServer(s)
+}
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		desc                string
+		path                string
+		upstreamCode        int
+		upstreamBody        string
+		expectStatus        int
+		expectForecastCount int
+		expectError         string
+		expectUnits         string
+	}{{
+		desc:                "forecast",
+		path:                "/spots/391/forecast",
+		upstreamCode:        200,
+		upstreamBody:        resp,
+		expectStatus:        200,
+		expectForecastCount: 3,
+	}, {
+		desc:                "today",
+		path:                "/spots/391/today",
+		upstreamCode:        200,
+		upstreamBody:        resp,
+		expectStatus:        200,
+		expectForecastCount: 1,
+	}, {
+		desc:                "tomorrow",
+		path:                "/spots/391/tomorrow",
+		upstreamCode:        200,
+		upstreamBody:        resp,
+		expectStatus:        200,
+		expectForecastCount: 1,
+	}, {
+		desc:                "weekend",
+		path:                "/spots/391/weekend",
+		upstreamCode:        200,
+		upstreamBody:        resp,
+		expectStatus:        200,
+		expectForecastCount: 1,
+	}, {
+		desc:                "forecast with units and a time range",
+		path:                "/spots/391/forecast?units=eu&from=2015-09-16T00:00:00Z&to=2016-01-01T00:00:00Z",
+		upstreamCode:        200,
+		upstreamBody:        resp,
+		expectStatus:        200,
+		expectForecastCount: 1,
+		expectUnits:         "eu",
+	}, {
+		desc:         "invalid units",
+		path:         "/spots/391/forecast?units=imperial",
+		expectStatus: 400,
+		expectError:  "units must be one of us, uk, or eu",
+	}, {
+		desc:         "invalid time range",
+		path:         "/spots/391/forecast?from=yesterday",
+		expectStatus: 400,
+		expectError:  "from must be an RFC3339 time",
+	}, {
+		desc:         "non-numeric spot ID",
+		path:         "/spots/ocean-city/forecast",
+		expectStatus: 400,
+		expectError:  "spot ID must be numeric",
+	}, {
+		desc:         "unknown endpoint",
+		path:         "/spots/391/yesterday",
+		expectStatus: 404,
+		expectError:  "not found",
+	}, {
+		desc:         "API error response",
+		path:         "/spots/391/forecast",
+		upstreamCode: 200,
+		upstreamBody: errorResp,
+		expectStatus: 502,
+		expectError:  "Unable to authenticate request: Ensure your API key is passed correctly. Refer to the API docs.",
+	}, {
+		desc:         "API invalid parameters error response",
+		path:         "/spots/391/forecast",
+		upstreamCode: 200,
+		upstreamBody: `{"error_response": {"code": 501, "error_msg": "Invalid parameters were supplied and did not pass our validation, please double check your request."}}`,
+		expectStatus: 404,
+		expectError:  "Invalid parameters were supplied and did not pass our validation, please double check your request.",
+	}, {
+		desc:         "API unlisted error response",
+		path:         "/spots/391/forecast",
+		upstreamCode: 200,
+		upstreamBody: `{"error_response": {"code": 999, "error_msg": "Something went wrong."}}`,
+		expectStatus: 502,
+		expectError:  "Something went wrong.",
+	}, {
+		desc:         "API HTTP 404",
+		path:         "/spots/391/forecast",
+		upstreamCode: 404,
+		expectStatus: 404,
+		expectError:  "spot not found",
+	}, {
+		desc:         "API HTTP 500",
+		path:         "/spots/391/forecast",
+		upstreamCode: 500,
+		expectStatus: 502,
+		expectError:  "upstream API error",
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			u := &upstream{code: test.upstreamCode, body: test.upstreamBody}
+			api, srv := testServer(u)
+			defer api.Close()
+			defer srv.Close()
+
+			res, err := http.Get(srv.URL + test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != test.expectStatus {
+				t.Errorf("expected status '%d'; got '%d'", test.expectStatus, res.StatusCode)
+			}
+
+			if strings.Contains(string(body), "secretKey") {
+				t.Errorf("expected response not to include the API key; got '%s'", body)
+			}
+
+			if test.expectError != "" {
+				var e errorBody
+				if err := json.Unmarshal(body, &e); err != nil {
+					t.Fatal(err)
+				}
+
+				if e.Error.Message != test.expectError {
+					t.Errorf("expected error '%s'; got '%s'", test.expectError, e.Error.Message)
+				}
+
+				return
+			}
+
+			var forecasts []seaweed.Forecast
+			if err := json.Unmarshal(body, &forecasts); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(forecasts) != test.expectForecastCount {
+				t.Errorf("expected '%d' forecasts; got '%d'", test.expectForecastCount, len(forecasts))
+			}
+
+			if units, _ := u.units.Load().(string); units != test.expectUnits {
+				t.Errorf("expected units '%s'; got '%s'", test.expectUnits, units)
+			}
+
+			if key, _ := u.key.Load().(string); key != "secretKey" {
+				t.Errorf("expected the API key to be injected; got '%s'", key)
+			}
+		})
+	}
+}
+
+func TestServer_cache(t *testing.T) {
+	u := &upstream{code: 200, body: resp}
+	api, srv := testServer(u)
+	defer api.Close()
+	defer srv.Close()
+
+	for _, path := range []string{"/spots/391/forecast", "/spots/391/today", "/spots/391/weekend"} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if cc := res.Header.Get("Cache-Control"); cc != "public, max-age=1800" {
+			t.Errorf("expected Cache-Control 'public, max-age=1800'; got '%s'", cc)
+		}
+	}
+
+	if u.requests.Load() != 1 {
+		t.Errorf("expected '1' upstream request; got '%d'", u.requests.Load())
+	}
+
+	res, err := http.Get(srv.URL + "/spots/391/forecast?units=uk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if u.requests.Load() != 2 {
+		t.Errorf("expected a distinct upstream request for distinct units; got '%d' requests", u.requests.Load())
+	}
+}
+
+// settableClock is a seaweed.Clock reporting a settable time.
+type settableClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *settableClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *settableClock) add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestServer_cacheControl(t *testing.T) {
+	clock := &settableClock{now: testClock{}.Now()}
+	u := &upstream{code: 200, body: resp}
+	api, srv := testServer(u, WithClock(clock))
+	defer api.Close()
+	defer srv.Close()
+
+	tests := []struct {
+		desc           string
+		advance        time.Duration
+		expectCC       string
+		expectRequests int32
+	}{{
+		desc:           "when the forecast is fetched",
+		expectCC:       "public, max-age=1800",
+		expectRequests: 1,
+	}, {
+		desc:           "when the forecast is cached",
+		advance:        10 * time.Minute,
+		expectCC:       "public, max-age=1200",
+		expectRequests: 1,
+	}, {
+		desc:           "when the cached forecast has expired",
+		advance:        20 * time.Minute,
+		expectCC:       "public, max-age=1800",
+		expectRequests: 2,
+	}}
+
+	for _, test := range tests {
+		clock.add(test.advance)
+
+		res, err := http.Get(srv.URL + "/spots/391/forecast")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if cc := res.Header.Get("Cache-Control"); cc != test.expectCC {
+			t.Errorf("%s: expected Cache-Control '%s'; got '%s'", test.desc, test.expectCC, cc)
+		}
+
+		if u.requests.Load() != test.expectRequests {
+			t.Errorf("%s: expected '%d' upstream requests; got '%d'", test.desc, test.expectRequests, u.requests.Load())
+		}
+	}
+}
+
+func TestServer_cacheEviction(t *testing.T) {
+	u := &upstream{code: 200, body: resp}
+	api, srv := testServer(u, WithMaxCachedSpots(1))
+	defer api.Close()
+	defer srv.Close()
+
+	for _, spot := range []string{"391", "392", "391"} {
+		res, err := http.Get(srv.URL + "/spots/" + spot + "/forecast")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	if u.requests.Load() != 3 {
+		t.Errorf("expected the least recently requested spot to be evicted; got '%d' upstream requests", u.requests.Load())
+	}
+}
@@ -0,0 +1,166 @@
+// Package solar calculates sunrise, sunset and civil twilight times for a
+// latitude, longitude and date, offline.
+//
+// Times are calculated per the sunrise equation, as used by NOAA's solar
+// calculator, and are accurate to within a minute or two at latitudes below
+// the polar circles.
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// j2000 is the Julian date of 2000-01-01 12:00 UTC.
+	j2000 = 2451545.0
+	// unixEpoch is the Julian date of 1970-01-01 00:00 UTC.
+	unixEpoch = 2440587.5
+	// obliquity is the Earth's axial tilt, in degrees.
+	obliquity = 23.4397
+
+	// Horizon is the sun's altitude, in degrees, at sunrise and sunset,
+	// accounting for atmospheric refraction and the sun's radius.
+	Horizon = -0.833
+	// CivilTwilight is the sun's altitude, in degrees, at the beginning of
+	// morning civil twilight and the end of evening civil twilight: first and
+	// last light.
+	CivilTwilight = -6.0
+)
+
+// Polar describes whether the sun crosses an altitude on a day.
+type Polar int
+
+const (
+	// Crosses reports that the sun rises above and sets below the altitude.
+	Crosses Polar = iota
+	// AlwaysAbove reports that the sun remains above the altitude all day,
+	// such as during the midnight sun.
+	AlwaysAbove
+	// AlwaysBelow reports that the sun remains below the altitude all day,
+	// such as during the polar night.
+	AlwaysBelow
+)
+
+// Day is the sun's daily course at a location. Its times are UTC; those the
+// sun doesn't cross on the day are zero.
+type Day struct {
+	// Dawn is the beginning of morning civil twilight: first light.
+	Dawn time.Time
+	// Sunrise is the time the sun's upper edge rises above the horizon.
+	Sunrise time.Time
+	// Noon is solar noon, when the sun is highest.
+	Noon time.Time
+	// Sunset is the time the sun's upper edge sets below the horizon.
+	Sunset time.Time
+	// Dusk is the end of evening civil twilight: last light.
+	Dusk time.Time
+	// Sun describes whether the sun rises and sets.
+	Sun Polar
+	// Twilight describes whether the sun crosses the civil twilight altitude.
+	Twilight Polar
+}
+
+// Compute returns the sun's course at the latitude and longitude, in degrees
+// north and east, on date's calendar day in date's location. Longitudes west
+// of Greenwich are negative.
+func Compute(date time.Time, lat, lon float64) Day {
+	y, m, d := date.Date()
+	noonUTC := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+
+	// n is the number of days since J2000; jStar is the mean solar noon at
+	// the longitude.
+	n := math.Round(julian(noonUTC) - j2000)
+	jStar := n - lon/360
+
+	// m is the solar mean anomaly; lambda is the ecliptic longitude.
+	mean := math.Mod(357.5291+0.98560028*jStar, 360)
+	mRad := radians(mean)
+	center := 1.9148*math.Sin(mRad) + 0.02*math.Sin(2*mRad) + 0.0003*math.Sin(3*mRad)
+	lambda := radians(math.Mod(mean+center+180+102.9372, 360))
+
+	transit := j2000 + jStar + 0.0053*math.Sin(mRad) - 0.0069*math.Sin(2*lambda)
+	declination := math.Asin(math.Sin(lambda) * math.Sin(radians(obliquity)))
+
+	day := Day{Noon: fromJulian(transit)}
+	day.Sunrise, day.Sunset, day.Sun = crossings(transit, lat, declination, Horizon)
+	day.Dawn, day.Dusk, day.Twilight = crossings(transit, lat, declination, CivilTwilight)
+
+	return day
+}
+
+// crossings returns the times at which the sun rises above and sets below the
+// altitude about the solar transit, or zero times and the Polar describing a
+// day on which it doesn't.
+func crossings(transit, lat, declination, altitude float64) (time.Time, time.Time, Polar) {
+	phi := radians(lat)
+	cosHourAngle := (math.Sin(radians(altitude)) - math.Sin(phi)*math.Sin(declination)) /
+		(math.Cos(phi) * math.Cos(declination))
+
+	switch {
+	case cosHourAngle < -1:
+		return time.Time{}, time.Time{}, AlwaysAbove
+	case cosHourAngle > 1:
+		return time.Time{}, time.Time{}, AlwaysBelow
+	}
+
+	hourAngle := degrees(math.Acos(cosHourAngle)) / 360
+
+	return fromJulian(transit - hourAngle), fromJulian(transit + hourAngle), Crosses
+}
+
+// Light returns true if t falls between first and last light, inclusive, or
+// the day has no civil night.
+func (d Day) Light(t time.Time) bool {
+	switch d.Twilight {
+	case AlwaysAbove:
+		return true
+	case AlwaysBelow:
+		return false
+	}
+
+	return !t.Before(d.Dawn) && !t.After(d.Dusk)
+}
+
+// Up returns true if the sun is above the horizon at t, per the day's sunrise
+// and sunset.
+func (d Day) Up(t time.Time) bool {
+	switch d.Sun {
+	case AlwaysAbove:
+		return true
+	case AlwaysBelow:
+		return false
+	}
+
+	return !t.Before(d.Sunrise) && !t.After(d.Sunset)
+}
+
+// Length returns the time between sunrise and sunset.
+func (d Day) Length() time.Duration {
+	switch d.Sun {
+	case AlwaysAbove:
+		return 24 * time.Hour
+	case AlwaysBelow:
+		return 0
+	}
+
+	return d.Sunset.Sub(d.Sunrise)
+}
+
+func julian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + unixEpoch
+}
+
+func fromJulian(j float64) time.Time {
+	secs := (j - unixEpoch) * 86400
+
+	return time.Unix(int64(math.Round(secs)), 0).UTC()
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
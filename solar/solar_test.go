@@ -0,0 +1,208 @@
+package solar
+
+import (
+	"testing"
+	"time"
+)
+
+// tolerance is the maximum difference between a calculated time and its
+// published almanac value, which is rounded to the minute.
+const tolerance = 2 * time.Minute
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %s", name, err)
+	}
+
+	return loc
+}
+
+func TestCompute(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	sydney := mustLoad(t, "Australia/Sydney")
+	london := mustLoad(t, "Europe/London")
+
+	// Published values are those of the US Naval Observatory's "Sun and Moon
+	// Data for One Day", in local time.
+	tests := []struct {
+		desc                        string
+		date                        time.Time
+		lat, lon                    float64
+		dawn, sunrise, sunset, dusk string
+	}{{
+		desc:    "New York City on the June solstice",
+		date:    time.Date(2024, 6, 20, 0, 0, 0, 0, newYork),
+		lat:     40.7128,
+		lon:     -74.0060,
+		dawn:    "04:52",
+		sunrise: "05:25",
+		sunset:  "20:31",
+		dusk:    "21:03",
+	}, {
+		desc:    "New York City on the December solstice",
+		date:    time.Date(2024, 12, 21, 0, 0, 0, 0, newYork),
+		lat:     40.7128,
+		lon:     -74.0060,
+		dawn:    "06:46",
+		sunrise: "07:17",
+		sunset:  "16:32",
+		dusk:    "17:03",
+	}, {
+		desc:    "Sydney on the December solstice",
+		date:    time.Date(2024, 12, 21, 0, 0, 0, 0, sydney),
+		lat:     -33.8688,
+		lon:     151.2093,
+		dawn:    "05:12",
+		sunrise: "05:41",
+		sunset:  "20:05",
+		dusk:    "20:34",
+	}, {
+		desc:    "Greenwich on 1 January 2000",
+		date:    time.Date(2000, 1, 1, 0, 0, 0, 0, london),
+		lat:     51.4769,
+		lon:     -0.0005,
+		dawn:    "07:26",
+		sunrise: "08:06",
+		sunset:  "16:01",
+		dusk:    "16:41",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			day := Compute(test.date, test.lat, test.lon)
+
+			if day.Sun != Crosses || day.Twilight != Crosses {
+				t.Fatalf("expected the sun to cross the horizon and civil twilight; got %v and %v", day.Sun, day.Twilight)
+			}
+
+			for _, c := range []struct {
+				name   string
+				got    time.Time
+				expect string
+			}{
+				{"dawn", day.Dawn, test.dawn},
+				{"sunrise", day.Sunrise, test.sunrise},
+				{"sunset", day.Sunset, test.sunset},
+				{"dusk", day.Dusk, test.dusk},
+			} {
+				clock, err := time.ParseInLocation("15:04", c.expect, test.date.Location())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				y, m, d := test.date.Date()
+				expect := time.Date(y, m, d, clock.Hour(), clock.Minute(), 0, 0, test.date.Location())
+
+				diff := c.got.Sub(expect)
+				if diff < 0 {
+					diff = -diff
+				}
+
+				if diff > tolerance {
+					t.Errorf("expected %s '%s'; got '%s'", c.name, expect, c.got.In(test.date.Location()))
+				}
+			}
+		})
+	}
+}
+
+func TestComputePolar(t *testing.T) {
+	tests := []struct {
+		desc           string
+		date           time.Time
+		sun, twilight  Polar
+		expectDawnDusk bool
+	}{{
+		desc:           "polar night with civil twilight",
+		date:           time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+		sun:            AlwaysBelow,
+		twilight:       Crosses,
+		expectDawnDusk: true,
+	}, {
+		desc:     "midnight sun",
+		date:     time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC),
+		sun:      AlwaysAbove,
+		twilight: AlwaysAbove,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			// Tromsø, Norway
+			day := Compute(test.date, 69.6492, 18.9553)
+
+			if day.Sun != test.sun {
+				t.Errorf("expected sun '%v'; got '%v'", test.sun, day.Sun)
+			}
+
+			if day.Twilight != test.twilight {
+				t.Errorf("expected twilight '%v'; got '%v'", test.twilight, day.Twilight)
+			}
+
+			if !day.Sunrise.IsZero() || !day.Sunset.IsZero() {
+				t.Errorf("expected no sunrise or sunset; got '%s' and '%s'", day.Sunrise, day.Sunset)
+			}
+
+			if (!day.Dawn.IsZero()) != test.expectDawnDusk {
+				t.Errorf("expected dawn '%v'; got '%s'", test.expectDawnDusk, day.Dawn)
+			}
+
+			noon := day.Noon
+			if day.Light(noon) != (test.twilight != AlwaysBelow) {
+				t.Errorf("expected light at noon '%v'; got '%v'", test.twilight != AlwaysBelow, day.Light(noon))
+			}
+
+			if day.Up(noon) != (test.sun == AlwaysAbove) {
+				t.Errorf("expected sun up at noon '%v'; got '%v'", test.sun == AlwaysAbove, day.Up(noon))
+			}
+		})
+	}
+}
+
+func TestDay(t *testing.T) {
+	day := Compute(time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), 40.7128, -74.0060)
+
+	tests := []struct {
+		desc      string
+		t         time.Time
+		light, up bool
+	}{{
+		desc: "before first light",
+		t:    day.Dawn.Add(-time.Minute),
+	}, {
+		desc:  "at first light",
+		t:     day.Dawn,
+		light: true,
+	}, {
+		desc:  "at noon",
+		t:     day.Noon,
+		light: true,
+		up:    true,
+	}, {
+		desc:  "after sunset",
+		t:     day.Sunset.Add(time.Minute),
+		light: true,
+	}, {
+		desc: "after last light",
+		t:    day.Dusk.Add(time.Minute),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := day.Light(test.t); got != test.light {
+				t.Errorf("expected light '%v'; got '%v'", test.light, got)
+			}
+
+			if got := day.Up(test.t); got != test.up {
+				t.Errorf("expected up '%v'; got '%v'", test.up, got)
+			}
+		})
+	}
+
+	// Day and night are near equal at the equinox.
+	if l := day.Length(); l < 12*time.Hour || l > 12*time.Hour+15*time.Minute {
+		t.Errorf("expected an equinox day length near 12h; got '%s'", l)
+	}
+}
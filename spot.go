@@ -0,0 +1,42 @@
+package seaweed
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SpotID is a Magic Seaweed spot ID, such as "391" for Ocean City, NJ.
+type SpotID string
+
+// ParseSpotURL returns the spot ID of a Magic Seaweed surf report URL, such as
+// 391 for https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/. The scheme
+// may be omitted.
+func ParseSpotURL(rawURL string) (SpotID, error) {
+	s := strings.TrimSpace(rawURL)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid spot URL %q: %w", rawURL, err)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "magicseaweed.com" {
+		return "", fmt.Errorf("invalid spot URL %q: not a magicseaweed.com URL", rawURL)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) != 2 || !strings.HasSuffix(segments[0], "-Surf-Report") {
+		return "", fmt.Errorf("invalid spot URL %q: not a surf report URL", rawURL)
+	}
+
+	if _, err := strconv.ParseUint(segments[1], 10, 64); err != nil {
+		return "", fmt.Errorf("invalid spot URL %q: spot ID %q is not numeric", rawURL, segments[1])
+	}
+
+	return SpotID(segments[1]), nil
+}
@@ -0,0 +1,64 @@
+package seaweed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpotURL(t *testing.T) {
+	tests := []struct {
+		desc      string
+		url       string
+		expect    SpotID
+		expectErr string
+	}{{
+		desc:   "surf report URL",
+		url:    "https://magicseaweed.com/Ocean-City-NJ-Surf-Report/391/",
+		expect: "391",
+	}, {
+		desc:   "without a scheme, www or trailing slash",
+		url:    "www.magicseaweed.com/Ocean-City-NJ-Surf-Report/391",
+		expect: "391",
+	}, {
+		desc:   "with a query",
+		url:    "http://magicseaweed.com/Pipeline-Backdoor-Surf-Report/616/?units=us",
+		expect: "616",
+	}, {
+		desc:      "another host",
+		url:       "https://example.com/Ocean-City-NJ-Surf-Report/391/",
+		expectErr: "not a magicseaweed.com URL",
+	}, {
+		desc:      "not a surf report",
+		url:       "https://magicseaweed.com/news/391/",
+		expectErr: "not a surf report URL",
+	}, {
+		desc:      "non-numeric spot ID",
+		url:       "https://magicseaweed.com/Ocean-City-NJ-Surf-Report/abc/",
+		expectErr: `spot ID "abc" is not numeric`,
+	}, {
+		desc:      "unparseable",
+		url:       "https://magicseaweed.com/%zz",
+		expectErr: "invalid spot URL",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := ParseSpotURL(test.url)
+			if test.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Errorf("expected error containing '%s'; got '%v'", test.expectErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected ParseSpotURL not to error; got '%v'", err)
+			}
+
+			if got != test.expect {
+				t.Errorf("expected '%s'; got '%s'", test.expect, got)
+			}
+		})
+	}
+}
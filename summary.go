@@ -0,0 +1,183 @@
+package seaweed
+
+import (
+	"math"
+	"time"
+
+	"github.com/mdb/seaweed/solar"
+)
+
+// DaySummary summarizes a single local day's forecasts.
+type DaySummary struct {
+	// Date is the local day, expressed as midnight UTC in the manner of
+	// Forecast.LocalTimestamp.
+	Date time.Time `json:"date"`
+	// Forecasts is the number of forecast timesteps summarized.
+	Forecasts int `json:"forecasts"`
+	// Unit is the unit of the breaking heights, such as "ft".
+	Unit string `json:"unit"`
+	// MinBreakingHeight is the day's lowest absolute minimum breaking height.
+	MinBreakingHeight float64 `json:"minBreakingHeight"`
+	// MaxBreakingHeight is the day's highest absolute maximum breaking height.
+	MaxBreakingHeight float64 `json:"maxBreakingHeight"`
+	// AvgBreakingHeight is the mean of each timestep's absolute breaking
+	// height range midpoint.
+	AvgBreakingHeight float64 `json:"avgBreakingHeight"`
+	// DominantSwell is the day's most energetic primary swell component,
+	// weighing height squared by period.
+	DominantSwell Component `json:"dominantSwell"`
+	// WindUnit is the unit of the wind speeds, such as "mph".
+	WindUnit string `json:"windUnit"`
+	// MaxWindSpeed is the day's highest wind speed.
+	MaxWindSpeed int `json:"maxWindSpeed"`
+	// MaxWindGusts is the day's highest wind gust speed.
+	MaxWindGusts int `json:"maxWindGusts"`
+	// PeakSolidRating is the day's highest SolidRating.
+	PeakSolidRating int `json:"peakSolidRating"`
+	// PeakFadedRating is the day's highest FadedRating.
+	PeakFadedRating int `json:"peakFadedRating"`
+	// Best is the day's best 3-hour forecast timestep; see Better.
+	Best Forecast `json:"best"`
+	// FirstLight is the beginning of the day's morning civil twilight, if
+	// summarized WithLight and the day has one.
+	FirstLight *time.Time `json:"firstLight,omitempty"`
+	// LastLight is the end of the day's evening civil twilight, if summarized
+	// WithLight and the day has one.
+	LastLight *time.Time `json:"lastLight,omitempty"`
+}
+
+// summaryOptions configures Summarize.
+type summaryOptions struct {
+	light    bool
+	lat, lon float64
+}
+
+// SummaryOption configures Summarize.
+type SummaryOption = func(o *summaryOptions)
+
+// WithLight is a SummaryOption annotating each DaySummary with the day's
+// first and last light at the latitude and longitude, in degrees north and
+// east.
+func WithLight(lat, lon float64) SummaryOption {
+	return func(o *summaryOptions) {
+		o.light = true
+		o.lat = lat
+		o.lon = lon
+	}
+}
+
+// Summarize groups forecasts by local day, per their LocalTimestamp, and
+// returns a DaySummary for each day in chronological order.
+func Summarize(forecasts []Forecast, opts ...SummaryOption) []DaySummary {
+	o := &summaryOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var summaries []DaySummary
+	index := map[time.Time]int{}
+
+	for _, f := range forecasts {
+		day := localDate(f)
+
+		i, ok := index[day]
+		if !ok {
+			i = len(summaries)
+			index[day] = i
+			summaries = append(summaries, DaySummary{
+				Date:              day,
+				Unit:              f.Swell.Unit,
+				WindUnit:          f.Wind.Unit,
+				MinBreakingHeight: math.Inf(1),
+				Best:              f,
+				DominantSwell:     f.Swell.Components.Primary,
+			})
+		}
+
+		summaries[i].add(f)
+	}
+
+	for i := range summaries {
+		s := &summaries[i]
+		s.AvgBreakingHeight /= float64(s.Forecasts)
+
+		if o.light {
+			s.light(o.lat, o.lon)
+		}
+	}
+
+	// Summaries are appended in order of first appearance; order them by date
+	// in the event the forecasts were not.
+	for i := 1; i < len(summaries); i++ {
+		for j := i; j > 0 && summaries[j].Date.Before(summaries[j-1].Date); j-- {
+			summaries[j], summaries[j-1] = summaries[j-1], summaries[j]
+		}
+	}
+
+	return summaries
+}
+
+// light annotates the summary with its day's first and last light.
+func (s *DaySummary) light(lat, lon float64) {
+	day := solar.Compute(s.Date, lat, lon)
+	if day.Twilight != solar.Crosses {
+		return
+	}
+
+	s.FirstLight = &day.Dawn
+	s.LastLight = &day.Dusk
+}
+
+// add accumulates f into the summary. AvgBreakingHeight accumulates a sum.
+func (s *DaySummary) add(f Forecast) {
+	s.Forecasts++
+	s.MinBreakingHeight = math.Min(s.MinBreakingHeight, float64(f.Swell.AbsMinBreakingHeight))
+	s.MaxBreakingHeight = math.Max(s.MaxBreakingHeight, float64(f.Swell.AbsMaxBreakingHeight))
+	s.AvgBreakingHeight += float64(f.Swell.AbsMinBreakingHeight+f.Swell.AbsMaxBreakingHeight) / 2
+
+	if energy(f.Swell.Components.Primary) > energy(s.DominantSwell) {
+		s.DominantSwell = f.Swell.Components.Primary
+	}
+
+	if int(f.Wind.Speed) > s.MaxWindSpeed {
+		s.MaxWindSpeed = int(f.Wind.Speed)
+	}
+
+	if int(f.Wind.Gusts) > s.MaxWindGusts {
+		s.MaxWindGusts = int(f.Wind.Gusts)
+	}
+
+	if int(f.SolidRating) > s.PeakSolidRating {
+		s.PeakSolidRating = int(f.SolidRating)
+	}
+
+	if int(f.FadedRating) > s.PeakFadedRating {
+		s.PeakFadedRating = int(f.FadedRating)
+	}
+
+	if Better(f, s.Best) {
+		s.Best = f
+	}
+}
+
+// energy approximates a swell component's relative energy as its height
+// squared multiplied by its period.
+func energy(c Component) float64 {
+	return float64(c.Height) * float64(c.Height) * float64(c.Period)
+}
+
+// Better returns true if forecast a is better than forecast b: it has more
+// solid stars, or as many solid stars and more faded stars, or as many of each
+// and lighter wind, or as much wind and a larger maximum breaking height.
+func Better(a, b Forecast) bool {
+	switch {
+	case a.SolidRating != b.SolidRating:
+		return a.SolidRating > b.SolidRating
+	case a.FadedRating != b.FadedRating:
+		return a.FadedRating > b.FadedRating
+	case a.Wind.Speed != b.Wind.Speed:
+		return a.Wind.Speed < b.Wind.Speed
+	default:
+		return a.Swell.AbsMaxBreakingHeight > b.Swell.AbsMaxBreakingHeight
+	}
+}
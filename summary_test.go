@@ -0,0 +1,167 @@
+package seaweed
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// golden compares got with the contents of the golden file at path, updating
+// the file instead when the -update flag is passed.
+func golden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expected, got) {
+		t.Errorf("expected %s:\n%s\ngot:\n%s", path, expected, got)
+	}
+}
+
+func TestSummarize_golden(t *testing.T) {
+	var forecasts []Forecast
+	if err := json.Unmarshal([]byte(resp), &forecasts); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.MarshalIndent(Summarize(forecasts), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden(t, "testdata/summary.golden.json", append(got, '\n'))
+}
+
+func TestSummarize(t *testing.T) {
+	day := int64(1677888000) // 2023-03-04 00:00 UTC
+	step := func(hour int64, min, max FlexFloat, primary Component, wind, gusts FlexInt64, solid, faded FlexInt) Forecast {
+		return Forecast{
+			Timestamp:      FlexInt64(day + hour*3600 + 5*3600),
+			LocalTimestamp: FlexInt64(day + hour*3600),
+			SolidRating:    solid,
+			FadedRating:    faded,
+			Swell: Swell{
+				AbsMinBreakingHeight: min,
+				AbsMaxBreakingHeight: max,
+				Unit:                 "ft",
+				Components:           Components{Primary: primary},
+			},
+			Wind: Wind{Speed: FlexInt(wind), Gusts: gusts, Unit: "mph"},
+		}
+	}
+
+	forecasts := []Forecast{
+		step(24, 1, 2, Component{Height: 2, Period: 8}, 5, 8, 0, 1),
+		step(0, 2, 3, Component{Height: 3, Period: 8}, 10, 15, 1, 0),
+		step(6, 3, 5, Component{Height: 3, Period: 12, Direction: 90}, 12, 20, 2, 1),
+		step(9, 3, 5, Component{Height: 3, Period: 10}, 6, 30, 2, 1),
+		step(21, 1.5, 2, Component{Height: 2, Period: 7}, 4, 6, 0, 2),
+	}
+
+	summaries := Summarize(forecasts)
+
+	if len(summaries) != 2 {
+		t.Fatalf("expected '2' day summaries; got '%d'", len(summaries))
+	}
+
+	s := summaries[0]
+
+	if !s.Date.Equal(time.Unix(day, 0)) || !summaries[1].Date.Equal(time.Unix(day+86400, 0)) {
+		t.Errorf("expected summaries ordered by day; got '%s' and '%s'", s.Date, summaries[1].Date)
+	}
+
+	if s.Forecasts != 4 || s.Unit != "ft" || s.WindUnit != "mph" {
+		t.Errorf("expected '4' forecasts in ft and mph; got '%d' in '%s' and '%s'", s.Forecasts, s.Unit, s.WindUnit)
+	}
+
+	if s.MinBreakingHeight != 1.5 || s.MaxBreakingHeight != 5 || s.AvgBreakingHeight != 3.0625 {
+		t.Errorf("expected breaking heights 1.5/5/3.0625; got '%v/%v/%v'", s.MinBreakingHeight, s.MaxBreakingHeight, s.AvgBreakingHeight)
+	}
+
+	if s.DominantSwell.Period != 12 || s.DominantSwell.Direction != 90 {
+		t.Errorf("expected the 12s swell to dominate; got '%+v'", s.DominantSwell)
+	}
+
+	if s.MaxWindSpeed != 12 || s.MaxWindGusts != 30 {
+		t.Errorf("expected max wind 12 gusting 30; got '%d' gusting '%d'", s.MaxWindSpeed, s.MaxWindGusts)
+	}
+
+	if s.PeakSolidRating != 2 || s.PeakFadedRating != 2 {
+		t.Errorf("expected peak ratings 2/2; got '%d/%d'", s.PeakSolidRating, s.PeakFadedRating)
+	}
+
+	if int64(s.Best.LocalTimestamp) != day+9*3600 {
+		t.Errorf("expected the lighter-wind 2 star 09:00 block to be best; got '%s'", time.Unix(int64(s.Best.LocalTimestamp), 0).UTC())
+	}
+}
+
+func TestSummarize_empty(t *testing.T) {
+	if summaries := Summarize(nil); len(summaries) != 0 {
+		t.Errorf("expected no summaries; got '%v'", summaries)
+	}
+}
+
+func TestSummarize_withLight(t *testing.T) {
+	forecasts := []Forecast{{
+		Timestamp:      1718892000, // 2024-06-20 14:00 UTC
+		LocalTimestamp: 1718877600, // 2024-06-20 10:00 local
+	}}
+
+	tests := []struct {
+		desc             string
+		opts             []SummaryOption
+		lat, lon         float64
+		expectFirstLight string
+		expectLastLight  string
+	}{{
+		desc: "without WithLight",
+	}, {
+		// New York City's first and last light on the June solstice are at
+		// 04:52 and 21:03 EDT.
+		desc:             "WithLight",
+		opts:             []SummaryOption{WithLight(40.7128, -74.0060)},
+		expectFirstLight: "2024-06-20 08:51",
+		expectLastLight:  "2024-06-21 01:03",
+	}, {
+		desc: "WithLight during the midnight sun",
+		opts: []SummaryOption{WithLight(69.6492, 18.9553)},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			s := Summarize(forecasts, test.opts...)[0]
+
+			for _, c := range []struct {
+				name   string
+				got    *time.Time
+				expect string
+			}{
+				{"first light", s.FirstLight, test.expectFirstLight},
+				{"last light", s.LastLight, test.expectLastLight},
+			} {
+				var got string
+				if c.got != nil {
+					got = c.got.UTC().Format("2006-01-02 15:04")
+				}
+
+				if got != c.expect {
+					t.Errorf("expected %s '%s'; got '%s'", c.name, c.expect, got)
+				}
+			}
+		})
+	}
+}
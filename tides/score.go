@@ -0,0 +1,94 @@
+package tides
+
+import (
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// Preference is a spot's preferred tide, such as "mid tide, rising" at a
+// point break that closes out at low tide.
+type Preference struct {
+	// Min and Max bound the preferred tide height, inclusive, in the series'
+	// units.
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	// Falloff is the distance outside the preferred range over which a
+	// tide's score decreases from 1 to 0. A Falloff of 0 scores tides
+	// outside the range 0.
+	Falloff float64 `json:"falloff,omitempty"`
+	// States are the preferred tide states; any state is preferred if it is
+	// empty.
+	States []State `json:"states,omitempty"`
+}
+
+// Score scores how well the tide suits the preference, from 0 for an
+// unsuitable tide to 1 for a tide within the preferred range and state.
+func (p Preference) Score(t Tide) float64 {
+	if len(p.States) > 0 {
+		preferred := false
+		for _, s := range p.States {
+			if s == t.State {
+				preferred = true
+			}
+		}
+
+		if !preferred {
+			return 0
+		}
+	}
+
+	var distance float64
+	switch {
+	case t.Height < p.Min:
+		distance = p.Min - t.Height
+	case t.Height > p.Max:
+		distance = t.Height - p.Max
+	default:
+		return 1
+	}
+
+	if p.Falloff <= 0 || distance >= p.Falloff {
+		return 0
+	}
+
+	return 1 - distance/p.Falloff
+}
+
+// Score scores the forecast's tide against the preference; see
+// Preference.Score. A forecast without a tide scores 0.
+func (f TidalForecast) Score(p Preference) float64 {
+	if f.Tide == nil {
+		return 0
+	}
+
+	return p.Score(*f.Tide)
+}
+
+// Scorer scores a spot's tidal forecasts, such as to rank them alongside
+// their star ratings.
+type Scorer func(spot string, f TidalForecast) float64
+
+// PreferenceScorer returns a Scorer scoring each spot's tidal forecasts
+// against its preference; see Preference.Score. Forecasts of spots without a
+// preference score 1, as any tide suits them.
+func PreferenceScorer(preferences map[string]Preference) Scorer {
+	return func(spot string, f TidalForecast) float64 {
+		p, ok := preferences[spot]
+		if !ok {
+			return 1
+		}
+
+		return f.Score(p)
+	}
+}
+
+// Preferred returns a seaweed.Predicate satisfied by forecasts whose tide,
+// according to the series, suits the preference, scoring at least minScore.
+func Preferred(series Series, p Preference, minScore float64) seaweed.Predicate {
+	return func(f seaweed.Forecast) bool {
+		tide, ok := series.At(time.Unix(int64(f.Timestamp), 0))
+
+		return ok && p.Score(tide) >= minScore
+	}
+}
@@ -0,0 +1,104 @@
+package tides
+
+import (
+	"testing"
+
+	"github.com/mdb/seaweed"
+)
+
+func TestPreferenceScore(t *testing.T) {
+	tests := []struct {
+		desc   string
+		pref   Preference
+		tide   Tide
+		expect float64
+	}{{
+		desc:   "within range",
+		pref:   Preference{Min: 1, Max: 3},
+		tide:   Tide{Height: 2, State: Rising},
+		expect: 1,
+	}, {
+		desc:   "outside range without falloff",
+		pref:   Preference{Min: 1, Max: 3},
+		tide:   Tide{Height: 3.1, State: Rising},
+		expect: 0,
+	}, {
+		desc:   "below range with falloff",
+		pref:   Preference{Min: 1, Max: 3, Falloff: 2},
+		tide:   Tide{Height: 0.5, State: Rising},
+		expect: 0.75,
+	}, {
+		desc:   "beyond falloff",
+		pref:   Preference{Min: 1, Max: 3, Falloff: 2},
+		tide:   Tide{Height: 5.5, State: Rising},
+		expect: 0,
+	}, {
+		desc:   "preferred state",
+		pref:   Preference{Min: 1, Max: 3, States: []State{Rising}},
+		tide:   Tide{Height: 2, State: Rising},
+		expect: 1,
+	}, {
+		desc:   "unpreferred state",
+		pref:   Preference{Min: 1, Max: 3, States: []State{Rising}},
+		tide:   Tide{Height: 2, State: Falling},
+		expect: 0,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.pref.Score(test.tide); !approx(got, test.expect) {
+				t.Errorf("expected '%g'; got '%g'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestPreferenceScorer(t *testing.T) {
+	score := PreferenceScorer(map[string]Preference{"391": {Min: 1, Max: 3}})
+
+	tests := []struct {
+		desc   string
+		spot   string
+		f      TidalForecast
+		expect float64
+	}{{
+		desc:   "preferred tide",
+		spot:   "391",
+		f:      TidalForecast{Tide: &Tide{Height: 2}},
+		expect: 1,
+	}, {
+		desc:   "no tide",
+		spot:   "391",
+		f:      TidalForecast{},
+		expect: 0,
+	}, {
+		desc:   "spot without a preference",
+		spot:   "616",
+		f:      TidalForecast{Tide: &Tide{Height: 10}},
+		expect: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := score(test.spot, test.f); got != test.expect {
+				t.Errorf("expected '%g'; got '%g'", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	series := parseFixture(t, "hilo.json")
+	forecasts := []seaweed.Forecast{
+		{Timestamp: seaweed.FlexInt64(at(3, 0).Unix())},  // near high tide
+		{Timestamp: seaweed.FlexInt64(at(6, 0).Unix())},  // mid tide, falling
+		{Timestamp: seaweed.FlexInt64(at(12, 0).Unix())}, // mid tide, rising
+		{Timestamp: seaweed.FlexInt64(at(23, 0).Unix())}, // beyond the series
+	}
+
+	got := seaweed.Filter(forecasts, Preferred(series, Preference{Min: 1.5, Max: 3.5, States: []State{Rising}}, 1))
+
+	if len(got) != 1 || int64(got[0].Timestamp) != at(12, 0).Unix() {
+		t.Errorf("expected only the rising mid tide; got '%v'", got)
+	}
+}
@@ -0,0 +1,203 @@
+// Package tides parses NOAA CO-OPS tide predictions and merges them into
+// forecasts, such that forecasts may be filtered and scored by tide.
+//
+// Predictions are fetched from the CO-OPS data API, such as
+// https://api.tidesandcurrents.noaa.gov/api/prod/datagetter?product=predictions&station=8534720&datum=MLLW&units=english&time_zone=gmt&format=json&begin_date=20261018&range=72,
+// optionally with interval=hilo for high and low tides only.
+package tides
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// timeLayout is the layout of CO-OPS prediction times.
+const timeLayout = "2006-01-02 15:04"
+
+// Prediction is a predicted tide height.
+type Prediction struct {
+	// Time is the time of the prediction.
+	Time time.Time `json:"time"`
+	// Height is the predicted height above the requested datum, in the
+	// requested units.
+	Height float64 `json:"height"`
+	// Type is "H" or "L" for high and low tide predictions, and empty for
+	// interval predictions.
+	Type string `json:"type,omitempty"`
+}
+
+// Series is a station's tide predictions, ordered by time: either high and
+// low tides, or a regular interval, such as every 6 minutes.
+type Series []Prediction
+
+// APIError is an error reported by the CO-OPS API.
+type APIError struct {
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return "CO-OPS API error: " + e.Message
+}
+
+// Parse parses a CO-OPS predictions JSON response. CO-OPS reports times
+// without a zone, in the time zone requested via the time_zone parameter;
+// loc is that time zone, such as time.UTC for time_zone=gmt.
+func Parse(r io.Reader, loc *time.Location) (Series, error) {
+	var resp struct {
+		Predictions []struct {
+			T    string `json:"t"`
+			V    string `json:"v"`
+			Type string `json:"type"`
+		} `json:"predictions"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decoding tide predictions: %w", err)
+	}
+
+	if resp.Error != nil {
+		return nil, &APIError{Message: resp.Error.Message}
+	}
+
+	if resp.Predictions == nil {
+		return nil, errors.New("decoding tide predictions: no predictions")
+	}
+
+	series := make(Series, len(resp.Predictions))
+	for i, p := range resp.Predictions {
+		t, err := time.ParseInLocation(timeLayout, p.T, loc)
+		if err != nil {
+			return nil, fmt.Errorf("decoding tide prediction %d: invalid time %q", i, p.T)
+		}
+
+		v, err := strconv.ParseFloat(p.V, 64)
+		if err != nil {
+			return nil, fmt.Errorf("decoding tide prediction %d: invalid height %q", i, p.V)
+		}
+
+		series[i] = Prediction{Time: t, Height: v, Type: p.Type}
+	}
+
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Time.Before(series[j].Time)
+	})
+
+	return series, nil
+}
+
+// State is whether the tide is rising or falling.
+type State string
+
+const (
+	// Rising is a rising, or flooding, tide.
+	Rising State = "rising"
+	// Falling is a falling, or ebbing, tide.
+	Falling State = "falling"
+)
+
+// Tide is the tide at a time.
+type Tide struct {
+	// Height is the tide height, in the series' units.
+	Height float64 `json:"height"`
+	// State is whether the tide is rising or falling.
+	State State `json:"state"`
+}
+
+// highLow returns true if the series holds high and low tides, rather than
+// interval predictions.
+func (s Series) highLow() bool {
+	return len(s) > 0 && s[0].Type != ""
+}
+
+// At returns the tide at t, and false if t falls outside the series.
+//
+// Between interval predictions, the height is interpolated linearly. Between
+// high and low tides, it is interpolated along a half cosine, approximating
+// the tide's sinusoidal curve.
+func (s Series) At(t time.Time) (Tide, bool) {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].Time.After(t)
+	})
+
+	// The final prediction is the end of the last interval.
+	if i == len(s) && len(s) > 1 && s[len(s)-1].Time.Equal(t) {
+		i = len(s) - 1
+	}
+
+	if i == 0 || i == len(s) {
+		return Tide{}, false
+	}
+
+	prev, next := s[i-1], s[i]
+	frac := float64(t.Sub(prev.Time)) / float64(next.Time.Sub(prev.Time))
+
+	if s.highLow() {
+		frac = (1 - math.Cos(math.Pi*frac)) / 2
+	}
+
+	return Tide{
+		Height: prev.Height + (next.Height-prev.Height)*frac,
+		State:  s.state(i),
+	}, true
+}
+
+// state returns the state of the tide between the predictions at i-1 and i.
+// Where they are level, the nearest subsequent, or else preceding, change in
+// height determines the state.
+func (s Series) state(i int) State {
+	for j := i; j < len(s); j++ {
+		if d := s[j].Height - s[j-1].Height; d != 0 {
+			return stateOf(d)
+		}
+	}
+
+	for j := i - 1; j > 0; j-- {
+		if d := s[j].Height - s[j-1].Height; d != 0 {
+			return stateOf(d)
+		}
+	}
+
+	return Rising
+}
+
+func stateOf(delta float64) State {
+	if delta > 0 {
+		return Rising
+	}
+
+	return Falling
+}
+
+// TidalForecast is a forecast enriched with the tide at its time.
+type TidalForecast struct {
+	seaweed.Forecast
+	// Tide is the tide at the forecast's Timestamp, or nil if the series
+	// does not cover it.
+	Tide *Tide `json:"tide,omitempty"`
+}
+
+// WithTides returns the forecasts enriched with the tide at each of their
+// Timestamps.
+func WithTides(forecasts []seaweed.Forecast, series Series) []TidalForecast {
+	enriched := make([]TidalForecast, len(forecasts))
+	for i, f := range forecasts {
+		enriched[i] = TidalForecast{Forecast: f}
+
+		if tide, ok := series.At(time.Unix(int64(f.Timestamp), 0)); ok {
+			enriched[i].Tide = &tide
+		}
+	}
+
+	return enriched
+}
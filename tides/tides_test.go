@@ -0,0 +1,223 @@
+package tides
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+func parseFixture(t *testing.T, name string) Series {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	series, err := Parse(f, time.UTC)
+	if err != nil {
+		t.Fatalf("expected Parse not to error; got '%v'", err)
+	}
+
+	return series
+}
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 10, 18, hour, minute, 0, 0, time.UTC)
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestParse(t *testing.T) {
+	series := parseFixture(t, "hilo.json")
+
+	if len(series) != 4 {
+		t.Fatalf("expected '4' predictions; got '%d'", len(series))
+	}
+
+	expect := Prediction{Time: at(2, 48), Height: 4.512, Type: "H"}
+	if series[0] != expect {
+		t.Errorf("expected '%+v'; got '%+v'", expect, series[0])
+	}
+}
+
+func TestParse_location(t *testing.T) {
+	loc := time.FixedZone("EDT", -4*60*60)
+
+	series, err := Parse(strings.NewReader(`{"predictions":[{"t":"2026-10-18 02:48","v":"4.512","type":"H"}]}`), loc)
+	if err != nil {
+		t.Fatalf("expected Parse not to error; got '%v'", err)
+	}
+
+	if !series[0].Time.Equal(at(6, 48)) {
+		t.Errorf("expected '%s'; got '%s'", at(6, 48), series[0].Time.UTC())
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		desc        string
+		input       string
+		expectError string
+	}{{
+		desc:        "API error",
+		input:       `{"error":{"message":"No Predictions data was found. Please make sure the Datum input is valid."}}`,
+		expectError: "CO-OPS API error: No Predictions data was found. Please make sure the Datum input is valid.",
+	}, {
+		desc:        "invalid JSON",
+		input:       `{"predictions":`,
+		expectError: "decoding tide predictions: unexpected EOF",
+	}, {
+		desc:        "no predictions",
+		input:       `{}`,
+		expectError: "decoding tide predictions: no predictions",
+	}, {
+		desc:        "invalid time",
+		input:       `{"predictions":[{"t":"18/10/2026","v":"1.0"}]}`,
+		expectError: `decoding tide prediction 0: invalid time "18/10/2026"`,
+	}, {
+		desc:        "invalid height",
+		input:       `{"predictions":[{"t":"2026-10-18 00:00","v":""}]}`,
+		expectError: `decoding tide prediction 0: invalid height ""`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(test.input), time.UTC)
+			if err == nil || err.Error() != test.expectError {
+				t.Errorf("expected '%s'; got '%v'", test.expectError, err)
+			}
+		})
+	}
+
+	_, err := Parse(strings.NewReader(`{"error":{"message":"nope"}}`), time.UTC)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected an *APIError; got '%v'", err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	hilo := parseFixture(t, "hilo.json")
+	sixMinute := parseFixture(t, "six_minute.json")
+
+	tests := []struct {
+		desc         string
+		series       Series
+		at           time.Time
+		expectOK     bool
+		expectHeight float64
+		expectState  State
+	}{{
+		desc:         "high and low at a high tide",
+		series:       hilo,
+		at:           at(2, 48),
+		expectOK:     true,
+		expectHeight: 4.512,
+		expectState:  Falling,
+	}, {
+		desc:         "high and low at mid tide",
+		series:       hilo,
+		at:           at(5, 57),
+		expectOK:     true,
+		expectHeight: (4.512 + 0.214) / 2,
+		expectState:  Falling,
+	}, {
+		desc:         "high and low a quarter of the way to high tide",
+		series:       hilo,
+		at:           at(10, 37).Add(30 * time.Second),
+		expectOK:     true,
+		expectHeight: 0.214 + (4.873-0.214)*(1-math.Cos(math.Pi/4))/2,
+		expectState:  Rising,
+	}, {
+		desc:         "high and low at the final low tide",
+		series:       hilo,
+		at:           at(21, 30),
+		expectOK:     true,
+		expectHeight: 0.102,
+		expectState:  Falling,
+	}, {
+		desc:   "high and low before the first prediction",
+		series: hilo,
+		at:     at(2, 47),
+	}, {
+		desc:   "high and low after the last prediction",
+		series: hilo,
+		at:     at(21, 31),
+	}, {
+		desc:         "interval between predictions",
+		series:       sixMinute,
+		at:           at(0, 9),
+		expectOK:     true,
+		expectHeight: 2.15,
+		expectState:  Rising,
+	}, {
+		desc:         "interval at slack water",
+		series:       sixMinute,
+		at:           at(0, 51),
+		expectOK:     true,
+		expectHeight: 2.7,
+		expectState:  Rising,
+	}, {
+		desc:   "empty",
+		series: Series{},
+		at:     at(0, 0),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			tide, ok := test.series.At(test.at)
+			if ok != test.expectOK {
+				t.Fatalf("expected ok '%t'; got '%t'", test.expectOK, ok)
+			}
+
+			if !approx(tide.Height, test.expectHeight) {
+				t.Errorf("expected height '%g'; got '%g'", test.expectHeight, tide.Height)
+			}
+
+			if tide.State != test.expectState {
+				t.Errorf("expected state '%s'; got '%s'", test.expectState, tide.State)
+			}
+		})
+	}
+}
+
+func TestWithTides(t *testing.T) {
+	forecasts := []seaweed.Forecast{
+		{Timestamp: seaweed.FlexInt64(at(0, 0).Unix())},
+		{Timestamp: seaweed.FlexInt64(at(5, 57).Unix())},
+	}
+
+	enriched := WithTides(forecasts, parseFixture(t, "hilo.json"))
+
+	if len(enriched) != 2 {
+		t.Fatalf("expected '2' forecasts; got '%d'", len(enriched))
+	}
+
+	if enriched[0].Tide != nil {
+		t.Errorf("expected no tide before the series; got '%+v'", enriched[0].Tide)
+	}
+
+	if enriched[1].Tide == nil || enriched[1].Tide.State != Falling || enriched[1].Timestamp != forecasts[1].Timestamp {
+		t.Errorf("expected a falling tide; got '%+v'", enriched[1])
+	}
+
+	b, err := json.Marshal(enriched[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"timestamp":1792303020`) || !strings.Contains(string(b), `"tide":{"height":2.363`) || !strings.Contains(string(b), `"state":"falling"}`) {
+		t.Errorf("expected the forecast's fields alongside its tide; got '%s'", b)
+	}
+}
@@ -0,0 +1,127 @@
+package seaweed
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field of a decoded Forecast.
+type FieldError struct {
+	// Index is the offending Forecast's index within the validated []Forecast.
+	// It is 0 when validating a single Forecast.
+	Index int
+	// Field is the offending field's JSON path, such as "swell.unit".
+	Field string
+	// Reason describes why the field is invalid.
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("[%d] %s: %s", e.Index, e.Field, e.Reason)
+}
+
+// ValidationError is returned when one or more decoded forecasts are invalid.
+// It lists each offending forecast index and field.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+
+	return fmt.Sprintf("invalid forecast: %s", strings.Join(msgs, "; "))
+}
+
+// Validate returns a *ValidationError if the forecast is missing required
+// fields or reports impossible values, such as negative heights or directions
+// outside of 0-360 degrees. Otherwise it returns nil.
+func (f Forecast) Validate() error {
+	if errs := f.validate(0); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// ValidateForecasts validates each of the forecasts it's passed, as well as
+// that their timestamps are strictly increasing. It returns a *ValidationError
+// listing all offending forecasts and fields, or nil if none are invalid.
+func ValidateForecasts(forecasts []Forecast) error {
+	var errs []FieldError
+	for i, f := range forecasts {
+		errs = append(errs, f.validate(i)...)
+
+		if i > 0 && f.Timestamp != 0 && f.Timestamp <= forecasts[i-1].Timestamp {
+			errs = append(errs, FieldError{i, "timestamp", fmt.Sprintf("%d does not follow %d", f.Timestamp, forecasts[i-1].Timestamp)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+func (f Forecast) validate(i int) []FieldError {
+	var errs []FieldError
+	missing := func(field string) {
+		errs = append(errs, FieldError{i, field, "missing"})
+	}
+	nonNegative := func(field string, v float64) {
+		if v < 0 {
+			errs = append(errs, FieldError{i, field, fmt.Sprintf("negative height %v", v)})
+		}
+	}
+	direction := func(field string, v float64) {
+		if v < 0 || v > 360 {
+			errs = append(errs, FieldError{i, field, fmt.Sprintf("direction %v out of range 0-360", v)})
+		}
+	}
+
+	if f.Timestamp == 0 {
+		missing("timestamp")
+	}
+
+	if f.LocalTimestamp == 0 {
+		missing("localTimestamp")
+	}
+
+	if f.IssueTimestamp == 0 {
+		missing("issueTimestamp")
+	}
+
+	if f.Swell.Unit == "" {
+		missing("swell.unit")
+	}
+
+	nonNegative("swell.minBreakingHeight", float64(f.Swell.MinBreakingHeight))
+	nonNegative("swell.absMinBreakingHeight", float64(f.Swell.AbsMinBreakingHeight))
+	nonNegative("swell.maxBreakingHeight", float64(f.Swell.MaxBreakingHeight))
+	nonNegative("swell.absMaxBreakingHeight", float64(f.Swell.AbsMaxBreakingHeight))
+
+	components := []struct {
+		name string
+		c    *Component
+	}{
+		{"combined", &f.Swell.Components.Combined},
+		{"primary", &f.Swell.Components.Primary},
+		{"secondary", f.Swell.Components.Secondary},
+		{"tertiary", f.Swell.Components.Tertiary},
+	}
+	for _, each := range components {
+		if each.c == nil {
+			continue
+		}
+
+		nonNegative("swell.components."+each.name+".height", float64(each.c.Height))
+		direction("swell.components."+each.name+".direction", float64(each.c.Direction))
+	}
+
+	direction("wind.direction", float64(f.Wind.Direction))
+
+	return errs
+}
@@ -0,0 +1,154 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func validForecast(ts int64) Forecast {
+	return Forecast{
+		Timestamp:      FlexInt64(ts),
+		LocalTimestamp: FlexInt64(ts),
+		IssueTimestamp: FlexInt64(ts),
+		Swell: Swell{
+			Unit: "ft",
+			Components: Components{
+				Primary: Component{Height: 3, Period: 10, Direction: 120},
+			},
+		},
+		Wind: Wind{Direction: 360},
+	}
+}
+
+func TestForecast_Validate(t *testing.T) {
+	if err := validForecast(1).Validate(); err != nil {
+		t.Errorf("expected a valid forecast not to error; got '%v'", err)
+	}
+
+	f := validForecast(1)
+	f.IssueTimestamp = 0
+	f.Swell.Unit = ""
+	f.Swell.AbsMinBreakingHeight = -1
+	f.Swell.Components.Secondary = &Component{Direction: 361}
+	f.Wind.Direction = -5
+
+	err := f.Validate()
+
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected a *ValidationError; got '%v'", err)
+	}
+
+	expected := []FieldError{
+		{0, "issueTimestamp", "missing"},
+		{0, "swell.unit", "missing"},
+		{0, "swell.absMinBreakingHeight", "negative height -1"},
+		{0, "swell.components.secondary.direction", "direction 361 out of range 0-360"},
+		{0, "wind.direction", "direction -5 out of range 0-360"},
+	}
+
+	if !reflect.DeepEqual(vErr.Errors, expected) {
+		t.Errorf("expected errors '%v'; got '%v'", expected, vErr.Errors)
+	}
+}
+
+func TestValidateForecasts(t *testing.T) {
+	tests := []struct {
+		desc        string
+		forecasts   []Forecast
+		expectError string
+	}{{
+		desc:      "when the forecasts are valid",
+		forecasts: []Forecast{validForecast(1), validForecast(2)},
+	}, {
+		desc:      "when there are no forecasts",
+		forecasts: []Forecast{},
+	}, {
+		desc:        "when the timestamps are not increasing",
+		forecasts:   []Forecast{validForecast(1), validForecast(3), validForecast(2)},
+		expectError: "invalid forecast: [2] timestamp: 2 does not follow 3",
+	}, {
+		desc:        "when a forecast is zero valued",
+		forecasts:   []Forecast{validForecast(1), {}},
+		expectError: "invalid forecast: [1] timestamp: missing; [1] localTimestamp: missing; [1] issueTimestamp: missing; [1] swell.unit: missing",
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateForecasts(test.forecasts)
+
+			if test.expectError == "" && err != nil {
+				t.Errorf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if test.expectError != "" && (err == nil || err.Error() != test.expectError) {
+				t.Errorf("expected error '%s'; got '%v'", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestForecast_strictDecoding(t *testing.T) {
+	var fs []map[string]interface{}
+	if err := json.Unmarshal([]byte(resp), &fs); err != nil {
+		t.Fatal(err)
+	}
+
+	fs[1]["surprise"] = true
+	unknownField, err := json.Marshal(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc                string
+		body                string
+		expectError         string
+		expectForecastCount int
+	}{{
+		desc:                "when the response is valid",
+		body:                resp,
+		expectForecastCount: 3,
+	}, {
+		desc:        "when the response contains an unknown field",
+		body:        string(unknownField),
+		expectError: `json: unknown field "surprise"`,
+	}, {
+		desc:        "when the response contains an invalid forecast",
+		body:        strings.Replace(resp, `"unit":"ft"`, `"unit":""`, 1),
+		expectError: "invalid forecast: [0] swell.unit: missing",
+	}}
+
+	for i := range tests {
+		test := tests[i]
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server, c := testServerAndClient(200, test.body)
+			defer server.Close()
+			WithStrictDecoding()(c)
+
+			forecasts, err := c.Forecast("123")
+
+			if test.expectError == "" && err != nil {
+				t.Errorf("expected '%s' not to error; got '%v'", test.desc, err)
+			}
+
+			if test.expectError != "" && (err == nil || !strings.HasSuffix(err.Error(), test.expectError)) {
+				t.Errorf("expected error ending in '%s'; got '%v'", test.expectError, err)
+			}
+
+			if len(forecasts) != test.expectForecastCount {
+				t.Errorf("expected '%d' forecasts; got '%d'", test.expectForecastCount, len(forecasts))
+			}
+		})
+	}
+}
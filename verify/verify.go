@@ -0,0 +1,319 @@
+// Package verify quantifies the skill of successive Magic Seaweed forecast
+// issues by lead time, the time between a forecast's issue and the time it
+// forecasts, such as how accurate swell height forecasts are 1, 3 and 5 days
+// out.
+//
+// Forecasts are verified against a Truth: by default, the latest issue
+// forecasting each time (see Latest), or an observation source such as a
+// buoy.
+package verify
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+// DefaultLeadBucket is the default width of the lead time buckets into which
+// Verify groups forecasts.
+const DefaultLeadBucket = 24 * time.Hour
+
+// Variables is a set of the forecast variables Verify verifies.
+type Variables uint
+
+const (
+	// Height is the combined swell height.
+	Height Variables = 1 << iota
+	// Period is the combined swell period.
+	Period
+	// WindSpeed is the wind speed.
+	WindSpeed
+	// Rating is the solid star rating.
+	Rating
+)
+
+// AllVariables is the set of every variable Verify verifies.
+const AllVariables = Height | Period | WindSpeed | Rating
+
+// Has returns true if vs includes each of the variables of v.
+func (vs Variables) Has(v Variables) bool {
+	return vs&v == v
+}
+
+// Truth reports the conditions against which forecasts for a time are
+// verified.
+type Truth interface {
+	// At returns the verifying conditions at t alongside the variables they
+	// report, and false if there are none. Forecasts are verified only
+	// against the variables reported, such that a truth lacking a variable,
+	// such as a buoy observation's star rating, never counts against them.
+	// A returned Forecast's IssueTimestamp, if any, is the issue time of the
+	// verifying conditions; forecasts issued at or after it are not verified
+	// against it.
+	At(t time.Time) (seaweed.Forecast, Variables, bool)
+}
+
+// TruthFunc is a function implementing Truth.
+type TruthFunc func(t time.Time) (seaweed.Forecast, Variables, bool)
+
+// At implements Truth.
+func (f TruthFunc) At(t time.Time) (seaweed.Forecast, Variables, bool) {
+	return f(t)
+}
+
+// Latest returns a Truth reporting, for each forecasted time, the forecast of
+// the most recent of the issues it's passed, and AllVariables. Times between
+// timesteps are interpolated; see seaweed.At.
+func Latest(issues ...[]seaweed.Forecast) Truth {
+	latest := map[seaweed.FlexInt64]seaweed.Forecast{}
+	for _, issue := range issues {
+		for _, f := range issue {
+			if prev, ok := latest[f.Timestamp]; !ok || f.IssueTimestamp > prev.IssueTimestamp {
+				latest[f.Timestamp] = f
+			}
+		}
+	}
+
+	analysis := make([]seaweed.Forecast, 0, len(latest))
+	for _, f := range latest {
+		analysis = append(analysis, f)
+	}
+
+	sort.Slice(analysis, func(i, j int) bool {
+		return analysis[i].Timestamp < analysis[j].Timestamp
+	})
+
+	return TruthFunc(func(t time.Time) (seaweed.Forecast, Variables, bool) {
+		f, ok := seaweed.At(analysis, t)
+
+		return f, AllVariables, ok
+	})
+}
+
+// Stats are error statistics of a forecast variable.
+type Stats struct {
+	// N is the number of forecasts verified.
+	N int `json:"n"`
+	// Bias is the mean error, forecast minus truth; a positive bias is an
+	// overforecast.
+	Bias float64 `json:"bias"`
+	// MAE is the mean absolute error.
+	MAE float64 `json:"mae"`
+	// RMSE is the root mean squared error.
+	RMSE float64 `json:"rmse"`
+}
+
+// accumulator accumulates the errors from which Stats are computed.
+type accumulator struct {
+	n                   int
+	sum, sumAbs, sumSqr float64
+}
+
+func (a *accumulator) add(forecast, truth float64) {
+	err := forecast - truth
+	a.n++
+	a.sum += err
+	a.sumAbs += math.Abs(err)
+	a.sumSqr += err * err
+}
+
+func (a *accumulator) stats() Stats {
+	if a.n == 0 {
+		return Stats{}
+	}
+
+	n := float64(a.n)
+
+	return Stats{
+		N:    a.n,
+		Bias: a.sum / n,
+		MAE:  a.sumAbs / n,
+		RMSE: math.Sqrt(a.sumSqr / n),
+	}
+}
+
+// LeadStats are the verification statistics of the forecasts whose lead time
+// falls within a bucket.
+type LeadStats struct {
+	// Lead is the start of the lead time bucket.
+	Lead time.Duration `json:"lead"`
+	// N is the number of forecasts verified against any variable.
+	N int `json:"n"`
+	// Height are the statistics of the combined swell height.
+	Height Stats `json:"height"`
+	// Period are the statistics of the combined swell period.
+	Period Stats `json:"period"`
+	// WindSpeed are the statistics of the wind speed.
+	WindSpeed Stats `json:"windSpeed"`
+	// Ratings is the number of forecasts whose solid star rating was
+	// verified. It is 0 where the truth reports no ratings, as buoy
+	// observations don't.
+	Ratings int `json:"ratings"`
+	// RatingHitRate is the fraction of the Ratings forecasts whose solid star
+	// rating matches the truth's.
+	RatingHitRate float64 `json:"ratingHitRate"`
+	// RatingNearRate is the fraction of the Ratings forecasts whose solid
+	// star rating is within one star of the truth's.
+	RatingNearRate float64 `json:"ratingNearRate"`
+}
+
+// leadAccumulator accumulates the statistics of a lead time bucket.
+type leadAccumulator struct {
+	height, period, windSpeed accumulator
+	n, rated, hits, near      int
+}
+
+// Report is the verification statistics of each lead time bucket, ordered by
+// lead time.
+type Report []LeadStats
+
+// String renders the report as a table. Statistics of no forecasts are
+// rendered as "-".
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-8s %5s  %-23s %-23s %-23s %5s %5s\n", "lead", "n", "height bias/mae/rmse", "period bias/mae/rmse", "wind bias/mae/rmse", "hit", "±1")
+
+	for _, s := range r {
+		fmt.Fprintf(&b, "%-8s %5d  %-23s %-23s %-23s %5s %5s\n",
+			formatLead(s.Lead), s.N, formatStats(s.Height), formatStats(s.Period), formatStats(s.WindSpeed),
+			formatRate(s.RatingHitRate, s.Ratings), formatRate(s.RatingNearRate, s.Ratings))
+	}
+
+	return b.String()
+}
+
+func formatLead(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+
+	return fmt.Sprintf("%gh", d.Hours())
+}
+
+func formatStats(s Stats) string {
+	if s.N == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%+.2f/%.2f/%.2f", s.Bias, s.MAE, s.RMSE)
+}
+
+func formatRate(rate float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+// options configures Verify.
+type options struct {
+	bucket time.Duration
+}
+
+// Option configures Verify.
+type Option = func(o *options)
+
+// WithLeadBucket is an Option configuring the width of the lead time buckets
+// into which Verify groups forecasts. It defaults to DefaultLeadBucket.
+func WithLeadBucket(d time.Duration) Option {
+	return func(o *options) {
+		o.bucket = d
+	}
+}
+
+// Verify verifies each forecast of each issue it's passed against truth and
+// returns the statistics of each lead time bucket, where a forecast's lead
+// time is its Timestamp less its IssueTimestamp. Each forecast is verified
+// only against the variables truth reports; forecasts for which truth reports
+// no conditions or no variables, and forecasts issued at or after the truth,
+// are skipped.
+func Verify(issues [][]seaweed.Forecast, truth Truth, opts ...Option) Report {
+	o := &options{bucket: DefaultLeadBucket}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.bucket <= 0 {
+		o.bucket = DefaultLeadBucket
+	}
+
+	buckets := map[time.Duration]*leadAccumulator{}
+	for _, issue := range issues {
+		for _, f := range issue {
+			lead := time.Duration(f.Timestamp-f.IssueTimestamp) * time.Second
+			if lead < 0 {
+				continue
+			}
+
+			t, vars, ok := truth.At(time.Unix(int64(f.Timestamp), 0))
+			if !ok || vars&AllVariables == 0 || (t.IssueTimestamp != 0 && t.IssueTimestamp <= f.IssueTimestamp) {
+				continue
+			}
+
+			bucket := lead - lead%o.bucket
+			acc, ok := buckets[bucket]
+			if !ok {
+				acc = &leadAccumulator{}
+				buckets[bucket] = acc
+			}
+
+			fc, tc := f.Swell.Components.Combined, t.Swell.Components.Combined
+			if vars.Has(Height) {
+				acc.height.add(float64(fc.Height), float64(tc.Height))
+			}
+
+			if vars.Has(Period) {
+				acc.period.add(float64(fc.Period), float64(tc.Period))
+			}
+
+			if vars.Has(WindSpeed) {
+				acc.windSpeed.add(float64(f.Wind.Speed), float64(t.Wind.Speed))
+			}
+
+			acc.n++
+			if !vars.Has(Rating) {
+				continue
+			}
+
+			acc.rated++
+			diff := int(f.SolidRating - t.SolidRating)
+			if diff == 0 {
+				acc.hits++
+			}
+
+			if diff >= -1 && diff <= 1 {
+				acc.near++
+			}
+		}
+	}
+
+	report := make(Report, 0, len(buckets))
+	for lead, acc := range buckets {
+		s := LeadStats{
+			Lead:      lead,
+			N:         acc.n,
+			Height:    acc.height.stats(),
+			Period:    acc.period.stats(),
+			WindSpeed: acc.windSpeed.stats(),
+			Ratings:   acc.rated,
+		}
+
+		if acc.rated > 0 {
+			s.RatingHitRate = float64(acc.hits) / float64(acc.rated)
+			s.RatingNearRate = float64(acc.near) / float64(acc.rated)
+		}
+
+		report = append(report, s)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Lead < report[j].Lead
+	})
+
+	return report
+}
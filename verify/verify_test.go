@@ -0,0 +1,189 @@
+package verify
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdb/seaweed"
+)
+
+const day = int64(24 * 60 * 60)
+
+// valid is the time forecast by each test issue.
+var valid = time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC).Unix()
+
+func forecast(issued int64, height seaweed.FlexFloat, period, speed, solid seaweed.FlexInt) seaweed.Forecast {
+	f := seaweed.Forecast{
+		Timestamp:      seaweed.FlexInt64(valid),
+		IssueTimestamp: seaweed.FlexInt64(issued),
+		SolidRating:    solid,
+		Wind:           seaweed.Wind{Speed: speed},
+	}
+	f.Swell.Components.Combined = seaweed.Component{Height: height, Period: period}
+
+	return f
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestVerify(t *testing.T) {
+	issues := [][]seaweed.Forecast{
+		{forecast(valid-5*day, 6, 12, 20, 4)},
+		{forecast(valid-3*day-3600, 2, 8, 10, 1)},
+		{forecast(valid-3*day, 5, 11, 14, 2)},
+		{forecast(valid-day, 4, 10, 12, 2)},
+		{forecast(valid, 3, 10, 10, 2)},
+	}
+
+	report := Verify(issues, Latest(issues...))
+
+	if len(report) != 3 {
+		t.Fatalf("expected '3' lead time buckets; got '%d'", len(report))
+	}
+
+	tests := []struct {
+		desc       string
+		stats      LeadStats
+		lead       time.Duration
+		n          int
+		height     Stats
+		windBias   float64
+		hitRate    float64
+		nearRate   float64
+		periodRMSE float64
+	}{{
+		desc:       "1 day",
+		stats:      report[0],
+		lead:       24 * time.Hour,
+		n:          1,
+		height:     Stats{N: 1, Bias: 1, MAE: 1, RMSE: 1},
+		windBias:   2,
+		hitRate:    1,
+		nearRate:   1,
+		periodRMSE: 0,
+	}, {
+		desc:       "3 days",
+		stats:      report[1],
+		lead:       72 * time.Hour,
+		n:          2,
+		height:     Stats{N: 2, Bias: 0.5, MAE: 1.5, RMSE: math.Sqrt(2.5)},
+		windBias:   2,
+		hitRate:    0.5,
+		nearRate:   1,
+		periodRMSE: math.Sqrt(2.5),
+	}, {
+		desc:       "5 days",
+		stats:      report[2],
+		lead:       120 * time.Hour,
+		n:          1,
+		height:     Stats{N: 1, Bias: 3, MAE: 3, RMSE: 3},
+		windBias:   10,
+		hitRate:    0,
+		nearRate:   0,
+		periodRMSE: 2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			s := test.stats
+			if s.Lead != test.lead {
+				t.Errorf("expected lead '%s'; got '%s'", test.lead, s.Lead)
+			}
+
+			if s.N != test.n || s.Ratings != test.n {
+				t.Errorf("expected '%d' forecasts and ratings; got '%d' and '%d'", test.n, s.N, s.Ratings)
+			}
+
+			if s.Height.N != test.height.N || !approx(s.Height.Bias, test.height.Bias) || !approx(s.Height.MAE, test.height.MAE) || !approx(s.Height.RMSE, test.height.RMSE) {
+				t.Errorf("expected height '%+v'; got '%+v'", test.height, s.Height)
+			}
+
+			if !approx(s.WindSpeed.Bias, test.windBias) {
+				t.Errorf("expected wind bias '%g'; got '%g'", test.windBias, s.WindSpeed.Bias)
+			}
+
+			if !approx(s.Period.RMSE, test.periodRMSE) {
+				t.Errorf("expected period RMSE '%g'; got '%g'", test.periodRMSE, s.Period.RMSE)
+			}
+
+			if !approx(s.RatingHitRate, test.hitRate) {
+				t.Errorf("expected hit rate '%g'; got '%g'", test.hitRate, s.RatingHitRate)
+			}
+
+			if !approx(s.RatingNearRate, test.nearRate) {
+				t.Errorf("expected near rate '%g'; got '%g'", test.nearRate, s.RatingNearRate)
+			}
+		})
+	}
+}
+
+func TestVerify_observations(t *testing.T) {
+	issues := [][]seaweed.Forecast{{forecast(valid-day, 4, 10, 12, 2)}}
+	// The observation reports no period, and, as observations don't, no
+	// rating.
+	observed := TruthFunc(func(ts time.Time) (seaweed.Forecast, Variables, bool) {
+		if ts.Unix() != valid {
+			return seaweed.Forecast{}, 0, false
+		}
+
+		return forecast(0, 5, 0, 15, 0), Height | WindSpeed, true
+	})
+
+	report := Verify(issues, observed, WithLeadBucket(12*time.Hour))
+	if len(report) != 1 {
+		t.Fatalf("expected '1' lead time bucket; got '%d'", len(report))
+	}
+
+	if report[0].Lead != 24*time.Hour {
+		t.Errorf("expected lead '%s'; got '%s'", 24*time.Hour, report[0].Lead)
+	}
+
+	if !approx(report[0].Height.Bias, -1) {
+		t.Errorf("expected height bias '-1'; got '%g'", report[0].Height.Bias)
+	}
+
+	if report[0].Period.N != 0 {
+		t.Errorf("expected no period to be verified; got '%+v'", report[0].Period)
+	}
+
+	if report[0].Ratings != 0 || report[0].RatingHitRate != 0 {
+		t.Errorf("expected no rating to be verified; got '%d' at a hit rate of '%g'", report[0].Ratings, report[0].RatingHitRate)
+	}
+}
+
+func TestVerify_noTruth(t *testing.T) {
+	issues := [][]seaweed.Forecast{{forecast(valid, 3, 10, 10, 2)}}
+
+	if report := Verify(issues, Latest(issues...)); len(report) != 0 {
+		t.Errorf("expected an empty report; got '%v'", report)
+	}
+}
+
+func TestReportString(t *testing.T) {
+	report := Report{{
+		Lead:           72 * time.Hour,
+		N:              2,
+		Height:         Stats{N: 2, Bias: 0.5, MAE: 1.5, RMSE: 1.58},
+		Ratings:        2,
+		RatingHitRate:  0.5,
+		RatingNearRate: 1,
+	}, {
+		Lead: 12 * time.Hour,
+	}}
+
+	got := report.String()
+	for _, expect := range []string{
+		"lead",
+		"3d           2  +0.50/1.50/1.58",
+		"  50%  100%",
+		"12h          0  -",
+	} {
+		if !strings.Contains(got, expect) {
+			t.Errorf("expected '%s' to contain '%s'", got, expect)
+		}
+	}
+}
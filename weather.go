@@ -0,0 +1,139 @@
+package seaweed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WeatherCode is a Magic Seaweed weather code, as reported by
+// Condition.Weather. Each code identifies one of the API's weather icons.
+type WeatherCode int
+
+// UnknownWeather is the WeatherCode of a Condition.Weather that isn't a code.
+const UnknownWeather WeatherCode = -1
+
+// Severity categorizes weather by how it affects a session.
+type Severity int
+
+const (
+	// SeverityUnknown is the Severity of unknown weather codes.
+	SeverityUnknown Severity = iota
+	// Fair is dry weather with good visibility.
+	Fair
+	// Dull is dry but overcast or murky weather.
+	Dull
+	// Wet is weather bringing rain, drizzle or showers.
+	Wet
+	// Wintry is weather bringing snow, sleet, hail or freezing rain.
+	Wintry
+	// Severe is weather, such as thunderstorms, warranting staying out of the
+	// water.
+	Severe
+)
+
+var severityNames = map[Severity]string{
+	SeverityUnknown: "unknown",
+	Fair:            "fair",
+	Dull:            "dull",
+	Wet:             "wet",
+	Wintry:          "wintry",
+	Severe:          "severe",
+}
+
+// String returns the severity's name, such as "wet".
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return severityNames[SeverityUnknown]
+}
+
+// weather describes a WeatherCode.
+type weather struct {
+	description string
+	severity    Severity
+	icon        string
+}
+
+// weatherCodes maps Magic Seaweed weather codes to their description, Severity
+// and icon identifier.
+//
+// The API doesn't document its weather codes, so the table holds only the
+// codes with a documented meaning, currently 22, light rain showers. Every
+// other code is unknown.
+var weatherCodes = map[WeatherCode]weather{
+	22: {"light rain showers", Wet, "showers-day"},
+}
+
+// unknownIcon is the icon identifier of unknown weather codes.
+const unknownIcon = "unknown"
+
+// ParseWeatherCode parses a Condition.Weather value, such as "22", into a
+// WeatherCode. It returns an error, alongside UnknownWeather, if s isn't a
+// non-negative integer. Codes absent from the weather code table parse
+// without error; see WeatherCode.Known.
+func ParseWeatherCode(s string) (WeatherCode, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return UnknownWeather, fmt.Errorf("invalid weather code %q", s)
+	}
+
+	return WeatherCode(n), nil
+}
+
+// WeatherCode returns the condition's Weather as a WeatherCode, or
+// UnknownWeather if it isn't a code.
+func (c Condition) WeatherCode() WeatherCode {
+	code, _ := ParseWeatherCode(string(c.Weather))
+
+	return code
+}
+
+// Known returns true if the code is in the weather code table. Unknown codes
+// report an "unknown weather" Description, SeverityUnknown and an "unknown"
+// Icon.
+func (w WeatherCode) Known() bool {
+	_, ok := weatherCodes[w]
+
+	return ok
+}
+
+// Description returns a human-readable description of the weather, such as
+// "light rain showers", or "unknown weather" if the code is absent from the
+// weather code table.
+func (w WeatherCode) Description() string {
+	if wc, ok := weatherCodes[w]; ok {
+		return wc.description
+	}
+
+	return "unknown weather"
+}
+
+// Severity returns the weather's Severity, or SeverityUnknown if the code is
+// absent from the weather code table.
+func (w WeatherCode) Severity() Severity {
+	return weatherCodes[w].severity
+}
+
+// Icon returns an identifier for an icon depicting the weather, such as
+// "showers-day", or "unknown" if the code is absent from the weather code
+// table.
+func (w WeatherCode) Icon() string {
+	if wc, ok := weatherCodes[w]; ok {
+		return wc.icon
+	}
+
+	return unknownIcon
+}
+
+// String returns the weather's description, noting the code if it isn't
+// Known, such as "unknown weather (99)".
+func (w WeatherCode) String() string {
+	if w.Known() || w == UnknownWeather {
+		return w.Description()
+	}
+
+	return fmt.Sprintf("%s (%d)", w.Description(), int(w))
+}
@@ -0,0 +1,176 @@
+package seaweed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseWeatherCode(t *testing.T) {
+	tests := []struct {
+		desc        string
+		s           string
+		expect      WeatherCode
+		expectError string
+	}{{
+		desc:   "a known code",
+		s:      "22",
+		expect: 22,
+	}, {
+		desc:   "an unknown code",
+		s:      "99",
+		expect: 99,
+	}, {
+		desc:   "surrounding whitespace",
+		s:      " 6 ",
+		expect: 6,
+	}, {
+		desc:        "an empty string",
+		s:           "",
+		expect:      UnknownWeather,
+		expectError: `invalid weather code ""`,
+	}, {
+		desc:        "a negative code",
+		s:           "-3",
+		expect:      UnknownWeather,
+		expectError: `invalid weather code "-3"`,
+	}, {
+		desc:        "a non-numeric code",
+		s:           "sunny",
+		expect:      UnknownWeather,
+		expectError: `invalid weather code "sunny"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			code, err := ParseWeatherCode(test.s)
+
+			if code != test.expect {
+				t.Errorf("expected '%d'; got '%d'", test.expect, code)
+			}
+
+			if test.expectError == "" && err != nil {
+				t.Errorf("expected no error; got '%v'", err)
+			}
+
+			if test.expectError != "" && (err == nil || err.Error() != test.expectError) {
+				t.Errorf("expected error '%s'; got '%v'", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestWeatherCode(t *testing.T) {
+	tests := []struct {
+		desc              string
+		code              WeatherCode
+		expectKnown       bool
+		expectDescription string
+		expectSeverity    Severity
+		expectIcon        string
+		expectString      string
+	}{{
+		desc:              "light rain showers",
+		code:              22,
+		expectKnown:       true,
+		expectDescription: "light rain showers",
+		expectSeverity:    Wet,
+		expectIcon:        "showers-day",
+		expectString:      "light rain showers",
+	}, {
+		desc:              "an undocumented code",
+		code:              1,
+		expectDescription: "unknown weather",
+		expectSeverity:    SeverityUnknown,
+		expectIcon:        "unknown",
+		expectString:      "unknown weather (1)",
+	}, {
+		desc:              "code 0",
+		code:              0,
+		expectDescription: "unknown weather",
+		expectSeverity:    SeverityUnknown,
+		expectIcon:        "unknown",
+		expectString:      "unknown weather (0)",
+	}, {
+		desc:              "an unknown code",
+		code:              99,
+		expectDescription: "unknown weather",
+		expectSeverity:    SeverityUnknown,
+		expectIcon:        "unknown",
+		expectString:      "unknown weather (99)",
+	}, {
+		desc:              "UnknownWeather",
+		code:              UnknownWeather,
+		expectDescription: "unknown weather",
+		expectSeverity:    SeverityUnknown,
+		expectIcon:        "unknown",
+		expectString:      "unknown weather",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := test.code.Known(); got != test.expectKnown {
+				t.Errorf("expected Known '%v'; got '%v'", test.expectKnown, got)
+			}
+
+			if got := test.code.Description(); got != test.expectDescription {
+				t.Errorf("expected Description '%s'; got '%s'", test.expectDescription, got)
+			}
+
+			if got := test.code.Severity(); got != test.expectSeverity {
+				t.Errorf("expected Severity '%s'; got '%s'", test.expectSeverity, got)
+			}
+
+			if got := test.code.Icon(); got != test.expectIcon {
+				t.Errorf("expected Icon '%s'; got '%s'", test.expectIcon, got)
+			}
+
+			if got := test.code.String(); got != test.expectString {
+				t.Errorf("expected String '%s'; got '%s'", test.expectString, got)
+			}
+		})
+	}
+}
+
+func TestWeatherCodes(t *testing.T) {
+	for code, w := range weatherCodes {
+		if w.description == "" || w.icon == "" || w.severity == SeverityUnknown {
+			t.Errorf("expected code '%d' to be fully described; got '%+v'", code, w)
+		}
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		expect   string
+	}{
+		{Fair, "fair"},
+		{Dull, "dull"},
+		{Wet, "wet"},
+		{Wintry, "wintry"},
+		{Severe, "severe"},
+		{SeverityUnknown, "unknown"},
+		{Severity(42), "unknown"},
+	}
+
+	for _, test := range tests {
+		if got := test.severity.String(); got != test.expect {
+			t.Errorf("expected '%s'; got '%s'", test.expect, got)
+		}
+	}
+}
+
+func TestCondition_WeatherCode(t *testing.T) {
+	var forecasts []Forecast
+	if err := json.Unmarshal([]byte(resp), &forecasts); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := forecasts[0].Condition.WeatherCode(); code != 22 || code.Description() != "light rain showers" {
+		t.Errorf("expected the fixture's weather to be light rain showers; got '%d' (%s)", code, code)
+	}
+
+	if code := (Condition{}).WeatherCode(); code != UnknownWeather {
+		t.Errorf("expected an empty Weather to be UnknownWeather; got '%d'", code)
+	}
+}